@@ -0,0 +1,326 @@
+// Command odfgen generates Go types for the Objects of an O-DF document,
+// typically a discovery tree with MetaData published by a device vendor.
+//
+// Usage:
+//
+//	odfgen [-p PACKAGE] [-o OUTPUT] FILE
+//
+// Every top-level Object becomes a struct type named after its type
+// attribute, or its id when it has none, and every InfoItem a field whose
+// Go type follows the format in its MetaData or the type of its first
+// value. Child Objects become fields of their own struct types. Objects
+// sharing a type name are merged. The fields carry the odf struct tags of
+// df.Encode and df.Decode, and every top-level type gets Object and Read
+// methods that use them. Every field X gets GetX and SetX accessors.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	pkg    = flag.String("p", "devices", "package name of the generated file")
+	output = flag.String("o", "", "output file name; standard output if empty")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("odfgen: ")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: odfgen [-p PACKAGE] [-o OUTPUT] FILE\n")
+		os.Exit(2)
+	}
+
+	data, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(data, *pkg, flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+var goTypes = map[string]string{
+	"xs:string":             "string",
+	"xs:boolean":            "bool",
+	"xs:double":             "float64",
+	"xs:decimal":            "float64",
+	"xs:float":              "float32",
+	"xs:integer":            "int64",
+	"xs:long":               "int64",
+	"xs:int":                "int32",
+	"xs:short":              "int16",
+	"xs:byte":               "int8",
+	"xs:nonNegativeInteger": "uint64",
+	"xs:unsignedLong":       "uint64",
+	"xs:unsignedInt":        "uint32",
+	"xs:unsignedShort":      "uint16",
+	"xs:unsignedByte":       "uint8",
+	"xs:dateTime":           "time.Time",
+}
+
+type field struct {
+	name, goType, tag, comment string
+}
+
+type structType struct {
+	name     string
+	topLevel bool
+	fields   []field
+	// names holds the Go names of the fields and methods.
+	names map[string]bool
+	// tags maps the odf name of every field to its index in fields.
+	tags map[string]int
+}
+
+type generator struct {
+	types    []*structType
+	byName   map[string]*structType
+	usesTime bool
+}
+
+func generate(data []byte, pkg, source string) ([]byte, error) {
+	objects, err := df.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{byName: map[string]*structType{}}
+	for i := range objects.Objects {
+		object := &objects.Objects[i]
+		if _, err := g.addObject(object, "", df.Path{object.ID()}); err != nil {
+			return nil, err
+		}
+	}
+	if len(g.types) == 0 {
+		return nil, fmt.Errorf("%s has no Objects", source)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by odfgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n\t\"fmt\"\n\t\"github.com/qlm-iot/qlm/df\"\n", pkg)
+	if g.usesTime {
+		fmt.Fprintf(&buf, "\t\"time\"\n")
+	}
+	fmt.Fprintf(&buf, ")\n")
+	for _, t := range g.types {
+		writeType(&buf, t)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// addObject adds the fields of object to the struct type it maps to and
+// returns that type's name.
+func (g *generator) addObject(object *df.Object, parent string, path df.Path) (string, error) {
+	base := object.Type
+	if base == "" {
+		base = object.ID()
+	}
+	name := parent + identifier(base)
+	t, ok := g.byName[name]
+	if !ok {
+		t = &structType{name: name, names: map[string]bool{"Object": true, "Read": true}, tags: map[string]int{}}
+		g.byName[name] = t
+		g.types = append(g.types, t)
+	}
+	t.topLevel = t.topLevel || parent == ""
+
+	for i := range object.InfoItems {
+		item := &object.InfoItems[i]
+		f, err := g.infoItemField(item, path.Child(item.Name))
+		if err != nil {
+			return "", err
+		}
+		t.add(item.Name, f)
+	}
+	for i := range object.Objects {
+		child := &object.Objects[i]
+		childPath := path.Child(child.ID())
+		typeName, err := g.addObject(child, name, childPath)
+		if err != nil {
+			return "", err
+		}
+		tag, err := structTag(child.ID(), child.Udef, "", childPath)
+		if err != nil {
+			return "", err
+		}
+		f := field{name: identifier(child.ID()), goType: typeName, tag: tag}
+		if d := child.DescriptionFor("en"); d != nil {
+			f.comment = strings.TrimSpace(d.Text)
+		}
+		t.add(child.ID(), f)
+	}
+	return name, nil
+}
+
+func (g *generator) infoItemField(item *df.InfoItem, path df.Path) (field, error) {
+	meta, err := item.Meta()
+	if err != nil {
+		return field{}, fmt.Errorf("%s: %v", path, err)
+	}
+	format := meta.Format
+	if format == "" && len(item.Values) > 0 {
+		format = item.Values[0].Type
+	}
+	goType, ok := goTypes[strings.TrimSpace(format)]
+	if !ok {
+		goType = "string"
+	}
+	if goType == "time.Time" {
+		g.usesTime = true
+	}
+
+	tag, err := structTag(item.Name, item.Udef, meta.Unit, path)
+	if err != nil {
+		return field{}, err
+	}
+	f := field{name: identifier(item.Name), goType: goType, tag: tag}
+
+	if item.Description != nil {
+		f.comment = strings.TrimSpace(item.Description.Text)
+	}
+	var notes []string
+	switch {
+	case !meta.CanWrite() && meta.CanRead():
+		notes = append(notes, "read-only")
+	case !meta.CanRead() && meta.CanWrite():
+		notes = append(notes, "write-only")
+	}
+	if meta.Unit != "" {
+		notes = append(notes, "in "+meta.Unit)
+	}
+	if meta.Latency != nil {
+		notes = append(notes, "latency "+strconv.Itoa(*meta.Latency))
+	}
+	if meta.Accuracy != nil {
+		notes = append(notes, "accuracy "+strconv.FormatFloat(*meta.Accuracy, 'g', -1, 64))
+	}
+	if len(notes) > 0 {
+		if f.comment != "" {
+			f.comment += "\n"
+		}
+		f.comment += f.name + " is " + strings.Join(notes, ", ") + "."
+	}
+	return f, nil
+}
+
+// add adds f for the odf name unless the type already has it, renaming f
+// when it or its accessors would clash with another field or method.
+func (t *structType) add(odfName string, f field) {
+	if _, ok := t.tags[odfName]; ok {
+		return
+	}
+	name := f.name
+	for i := 2; t.names[name] || t.names["Get"+name] || t.names["Set"+name]; i++ {
+		name = f.name + strconv.Itoa(i)
+	}
+	f.name = name
+	t.names[name], t.names["Get"+name], t.names["Set"+name] = true, true, true
+	t.tags[odfName] = len(t.fields)
+	t.fields = append(t.fields, f)
+}
+
+func structTag(name, udef, unit string, path df.Path) (string, error) {
+	if name == "" || strings.ContainsAny(name+udef+unit, ",`") {
+		return "", fmt.Errorf("%s: name, udef or unit cannot be used in a struct tag", path)
+	}
+	value := name
+	if udef != "" {
+		value += ",udef=" + udef
+	}
+	if unit != "" {
+		value += ",unit=" + unit
+	}
+	return "`odf:" + strconv.Quote(value) + "`", nil
+}
+
+// identifier turns an O-DF name such as "Consumed Electrical Power Measure"
+// into an exported Go identifier such as ConsumedElectricalPowerMeasure.
+func identifier(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteString("X")
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "X"
+	}
+	return b.String()
+}
+
+func writeType(buf *bytes.Buffer, t *structType) {
+	fmt.Fprintf(buf, "\ntype %s struct {\n", t.name)
+	for _, f := range t.fields {
+		if f.comment != "" {
+			for _, line := range strings.Split(f.comment, "\n") {
+				fmt.Fprintf(buf, "\t// %s\n", strings.TrimSpace(line))
+			}
+		}
+		fmt.Fprintf(buf, "\t%s %s %s\n", f.name, f.goType, f.tag)
+	}
+	fmt.Fprintf(buf, "}\n")
+	for _, f := range t.fields {
+		fmt.Fprintf(buf, `
+// Get%[2]s returns %[2]s, or its zero value if o is nil.
+func (o *%[1]s) Get%[2]s() (v %[3]s) {
+	if o != nil {
+		v = o.%[2]s
+	}
+	return
+}
+
+// Set%[2]s sets %[2]s to v.
+func (o *%[1]s) Set%[2]s(v %[3]s) {
+	o.%[2]s = v
+}
+`, t.name, f.name, f.goType)
+	}
+	if !t.topLevel {
+		return
+	}
+
+	fmt.Fprintf(buf, `
+// Object encodes o as the Object with the given id.
+func (o *%[1]s) Object(id string) (df.Object, error) {
+	return df.Encode(id, o)
+}
+
+// Read sets o from the top-level Object with the given id in objects.
+func (o *%[1]s) Read(objects *df.Objects, id string) error {
+	object := objects.Object(df.Path{id})
+	if object == nil {
+		return fmt.Errorf("%%s not found", df.Path{id})
+	}
+	return df.Decode(*object, o)
+}
+`, t.name)
+}
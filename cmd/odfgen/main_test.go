@@ -0,0 +1,147 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// typeCheck type checks the generated source together with the Go files
+// in uses, which may refer to the generated types.
+func typeCheck(t *testing.T, src []byte, uses ...string) {
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for i, s := range append([]string{string(src)}, uses...) {
+		file, err := parser.ParseFile(fset, "file"+string(rune('0'+i))+".go", s, 0)
+		if !assert.Nil(t, err) {
+			return
+		}
+		files = append(files, file)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err := conf.Check("fridges", fset, files, nil)
+	assert.Nil(t, err)
+}
+
+func compact(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestGenerate(t *testing.T) {
+	data, err := ioutil.ReadFile("../../df/examples/metadata_about_refrigerator_power_consumption.xml")
+	if assert.Nil(t, err) {
+		out, err := generate(data, "fridges", "fridge.xml")
+		if assert.Nil(t, err) {
+			src := compact(string(out))
+			assert.True(t, strings.HasPrefix(src, "// Code generated by odfgen from fridge.xml. DO NOT EDIT. package fridges "))
+			assert.True(t, strings.Contains(src, "type SmartFridge22334411 struct {"))
+			assert.True(t, strings.Contains(src, "// PowerConsumption is read-only, in Watts, latency 5, accuracy 1. "))
+			assert.True(t, strings.Contains(src, "PowerConsumption float64 `odf:\"PowerConsumption,unit=Watts\"`"))
+			assert.True(t, strings.Contains(src, "func (o *SmartFridge22334411) Object(id string) (df.Object, error) {"))
+			assert.True(t, strings.Contains(src, "func (o *SmartFridge22334411) Read(objects *df.Objects, id string) error {"))
+			assert.True(t, strings.Contains(src, "func (o *SmartFridge22334411) GetPowerConsumption() (v float64) {"))
+			assert.True(t, strings.Contains(src, "func (o *SmartFridge22334411) SetPowerConsumption(v float64) {"))
+			assert.False(t, strings.Contains(src, `"time"`))
+			typeCheck(t, out, `package fridges
+
+import "github.com/qlm-iot/qlm/df"
+
+func use(objects *df.Objects) (float64, error) {
+	var f *SmartFridge22334411
+	_ = f.GetPowerConsumption()
+	f = &SmartFridge22334411{}
+	f.SetPowerConsumption(120)
+	if _, err := f.Object("SmartFridge22334411"); err != nil {
+		return 0, err
+	}
+	err := f.Read(objects, "SmartFridge22334411")
+	return f.GetPowerConsumption(), err
+}
+`)
+		}
+	}
+}
+
+func TestGenerateNestedObjects(t *testing.T) {
+	src := `<Objects>
+  <Object type="Fridge">
+    <id>Fridge1</id>
+    <InfoItem name="Serial Number" udef="mu3"><value type="xs:string">A1</value></InfoItem>
+    <InfoItem name="Serviced"><value type="xs:dateTime">2016-03-01T12:00:00Z</value></InfoItem>
+    <Object>
+      <id>Door</id>
+      <description>The front door.</description>
+      <InfoItem name="open"><value type="xs:boolean">false</value></InfoItem>
+    </Object>
+  </Object>
+  <Object type="Fridge">
+    <id>Fridge2</id>
+    <InfoItem name="Serial Number"><value type="xs:string">A2</value></InfoItem>
+    <InfoItem name="Starts"><MetaData><InfoItem name="format"><value>xs:unsignedInt</value></InfoItem></MetaData></InfoItem>
+    <InfoItem name="Read"/>
+  </Object>
+</Objects>`
+	out, err := generate([]byte(src), "p", "fridges.xml")
+	if assert.Nil(t, err) {
+		src := compact(string(out))
+		assert.True(t, strings.Contains(src, `"time"`))
+		assert.Equal(t, 1, strings.Count(src, "type Fridge struct {"))
+		assert.True(t, strings.Contains(src, "SerialNumber string `odf:\"Serial Number,udef=mu3\"`"))
+		assert.True(t, strings.Contains(src, "Serviced time.Time `odf:\"Serviced\"`"))
+		assert.True(t, strings.Contains(src, "// The front door. Door FridgeDoor `odf:\"Door\"`"))
+		assert.True(t, strings.Contains(src, "Starts uint32 `odf:\"Starts\"`"))
+		assert.True(t, strings.Contains(src, "Read2 string `odf:\"Read\"`"))
+		assert.True(t, strings.Contains(src, "type FridgeDoor struct { Open bool `odf:\"open\"` }"))
+		assert.False(t, strings.Contains(src, "func (o *FridgeDoor) Object("))
+		assert.True(t, strings.Contains(src, "func (o *FridgeDoor) GetOpen() (v bool) {"))
+		assert.True(t, strings.Contains(src, "func (o *Fridge) GetDoor() (v FridgeDoor) {"))
+		typeCheck(t, out)
+	}
+}
+
+func TestGenerateTopLevelTypeSeenNestedFirst(t *testing.T) {
+	src := `<Objects>
+  <Object type="Fridge">
+    <id>Fridge1</id>
+    <Object>
+      <id>Door</id>
+      <InfoItem name="Object"/>
+    </Object>
+  </Object>
+  <Object type="FridgeDoor">
+    <id>Door1</id>
+    <InfoItem name="Open"/>
+    <InfoItem name="GetOpen"/>
+  </Object>
+</Objects>`
+	out, err := generate([]byte(src), "p", "doors.xml")
+	if assert.Nil(t, err) {
+		src := compact(string(out))
+		assert.Equal(t, 1, strings.Count(src, "type FridgeDoor struct {"))
+		assert.True(t, strings.Contains(src, "Object2 string `odf:\"Object\"`"))
+		assert.True(t, strings.Contains(src, "GetOpen2 string `odf:\"GetOpen\"`"))
+		assert.True(t, strings.Contains(src, "func (o *FridgeDoor) Object(id string) (df.Object, error) {"))
+		assert.True(t, strings.Contains(src, "func (o *FridgeDoor) Read(objects *df.Objects, id string) error {"))
+		typeCheck(t, out)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := generate([]byte(`<Objects/>`), "p", "empty.xml")
+	assert.NotNil(t, err)
+	_, err = generate([]byte(`<Objects><Object><id>A</id><InfoItem name="a,b"/></Object></Objects>`), "p", "bad.xml")
+	assert.NotNil(t, err)
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "ConsumedElectricalPowerMeasure", identifier("Consumed Electrical Power Measure"))
+	assert.Equal(t, "SmartFridge22334411", identifier("SmartFridge22334411"))
+	assert.Equal(t, "X3Phase", identifier("3-phase"))
+	assert.Equal(t, "X", identifier("--"))
+}
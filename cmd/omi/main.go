@@ -0,0 +1,76 @@
+// Command omi sends O-MI requests to a node and prints the response.
+//
+// Usage:
+//
+//	omi [flags] read [PATH...]
+//	omi [flags] write PATH=VALUE...
+//	omi [flags] subscribe PATH...
+//	omi [flags] poll REQUESTID...
+//	omi [flags] cancel REQUESTID...
+//
+// PATHs name InfoItems, e.g. Objects/SmartFridge22334411/PowerConsumption.
+// A PATH ending in a slash or with a single element names an Object
+// instead. A read without PATHs discovers the Objects of the node.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/qlm-iot/qlm/mi"
+	"os"
+)
+
+var (
+	node     = flag.String("node", os.Getenv("OMI_NODE"), "URL of the O-MI node (default $OMI_NODE)")
+	ttl      = flag.Float64("ttl", 10, "time to live of the request in seconds")
+	jsonOut  = flag.Bool("json", false, "print the response as JSON with decoded payloads")
+	interval = flag.Float64("interval", -1, "subscription interval in seconds, -1 for event based")
+	newest   = flag.Int("newest", 0, "read only the newest n values")
+	oldest   = flag.Int("oldest", 0, "read only the oldest n values")
+	begin    = flag.String("begin", "", "read values from this time on")
+	end      = flag.String("end", "", "read values up to this time")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: omi [flags] read [PATH...] | write|subscribe|poll|cancel ARGS...\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 || *node == "" {
+		usage()
+	}
+
+	envelope, err := buildEnvelope(flag.Arg(0), flag.Args()[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "omi: %v\n", err)
+		os.Exit(2)
+	}
+
+	client := &mi.Client{}
+	reply, err := client.Send(*node, envelope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "omi: %v\n", err)
+		os.Exit(1)
+	}
+	if reply.Response == nil {
+		fmt.Fprintf(os.Stderr, "omi: %s sent no response\n", *node)
+		os.Exit(1)
+	}
+
+	if *jsonOut {
+		err = printJSON(os.Stdout, reply.Response)
+	} else {
+		err = printResponse(os.Stdout, reply.Response)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "omi: %v\n", err)
+		os.Exit(1)
+	}
+	if !succeeded(reply.Response) {
+		os.Exit(1)
+	}
+}
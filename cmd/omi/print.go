@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"io"
+	"strings"
+	"time"
+)
+
+func printResponse(w io.Writer, response *mi.Response) error {
+	for i, result := range response.Results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if result.Return != nil {
+			fmt.Fprintf(w, "return: %s", result.Return.ReturnCode)
+			if result.Return.Description != "" {
+				fmt.Fprintf(w, " (%s)", result.Return.Description)
+			}
+			fmt.Fprintln(w)
+		}
+		if result.RequestId != nil {
+			fmt.Fprintf(w, "requestId: %s\n", result.RequestId.Text)
+		}
+		if result.NodeList != nil {
+			fmt.Fprintf(w, "nodes: %s\n", strings.Join(result.NodeList.Nodes, " "))
+		}
+		if result.Message != nil {
+			fmt.Fprintf(w, "%s\n", formatMessage(result.MsgFormat, result.Message.Data))
+		}
+	}
+	return nil
+}
+
+func formatMessage(format, data string) string {
+	if format == "" || format == "odf" {
+		if objects, err := df.Unmarshal([]byte(data)); err == nil {
+			if formatted, err := df.Marshal(*objects); err == nil {
+				return string(formatted)
+			}
+		}
+	}
+	return strings.TrimSpace(data)
+}
+
+type jsonResult struct {
+	ReturnCode  string      `json:"returnCode,omitempty"`
+	Description string      `json:"description,omitempty"`
+	RequestId   string      `json:"requestId,omitempty"`
+	Nodes       []string    `json:"nodes,omitempty"`
+	MsgFormat   string      `json:"msgformat,omitempty"`
+	NextOffset  int         `json:"nextOffset,omitempty"`
+	Payload     interface{} `json:"payload,omitempty"`
+}
+
+type jsonObject struct {
+	Id        string         `json:"id"`
+	Type      string         `json:"type,omitempty"`
+	InfoItems []jsonInfoItem `json:"infoItems,omitempty"`
+	Objects   []jsonObject   `json:"objects,omitempty"`
+}
+
+type jsonInfoItem struct {
+	Name   string      `json:"name"`
+	Values []jsonValue `json:"values,omitempty"`
+}
+
+type jsonValue struct {
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+	Time  string `json:"time,omitempty"`
+}
+
+// printJSON prints the results with their payloads decoded; O-DF payloads
+// become trees of Objects and InfoItems, and payloads without a codec are
+// printed as text.
+func printJSON(w io.Writer, response *mi.Response) error {
+	results := []jsonResult{}
+	for _, result := range response.Results {
+		r := jsonResult{MsgFormat: result.MsgFormat, NextOffset: result.NextOffset}
+		if result.Return != nil {
+			r.ReturnCode = result.Return.ReturnCode
+			r.Description = result.Return.Description
+		}
+		if result.RequestId != nil {
+			r.RequestId = result.RequestId.Text
+		}
+		if result.NodeList != nil {
+			r.Nodes = result.NodeList.Nodes
+		}
+		if result.Message != nil {
+			payload, err := mi.DecodePayload(result.MsgFormat, result.Message)
+			if err != nil {
+				return err
+			}
+			switch p := payload.(type) {
+			case *df.Objects:
+				r.Payload = jsonObjects(p.Objects)
+			case mi.Raw:
+				r.Payload = strings.TrimSpace(p.Data)
+			default:
+				r.Payload = p
+			}
+		}
+		results = append(results, r)
+	}
+
+	data, err := json.MarshalIndent(results, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+func succeeded(response *mi.Response) bool {
+	for _, result := range response.Results {
+		if result.Return != nil && !strings.HasPrefix(result.Return.ReturnCode, "2") {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonObjects(objects []df.Object) []jsonObject {
+	converted := []jsonObject{}
+	for _, object := range objects {
+		o := jsonObject{Id: object.ID(), Type: object.Type, Objects: jsonObjects(object.Objects)}
+		for _, item := range object.InfoItems {
+			i := jsonInfoItem{Name: item.Name}
+			for _, value := range item.Values {
+				v := jsonValue{Value: value.Text, Type: value.Type}
+				if t, ok := value.Time(); ok {
+					v.Time = t.Format(time.RFC3339)
+				}
+				i.Values = append(i.Values, v)
+			}
+			o.InfoItems = append(o.InfoItems, i)
+		}
+		converted = append(converted, o)
+	}
+	return converted
+}
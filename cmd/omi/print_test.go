@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/qlm-iot/qlm/mi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrintJSON(t *testing.T) {
+	response := &mi.Response{Results: []mi.RequestResult{
+		mi.RequestResult{
+			Return:    &mi.Return{ReturnCode: "200"},
+			MsgFormat: "odf",
+			Message: &mi.Message{Data: `<Objects><Object><id>SmartFridge22334411</id>
+				<InfoItem name="PowerConsumption"><value unixTime="1412775405">43</value></InfoItem>
+				</Object></Objects>`},
+		},
+		mi.RequestResult{
+			Return:    &mi.Return{ReturnCode: "200"},
+			MsgFormat: "obix",
+			Message:   &mi.Message{Data: ` <obj/> `},
+		},
+		mi.RequestResult{
+			Return:    &mi.Return{ReturnCode: "404", Description: "Not Found"},
+			RequestId: &mi.Id{Text: "REQ1"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if assert.Nil(t, printJSON(&buf, response)) {
+		var v []map[string]interface{}
+		if assert.Nil(t, json.Unmarshal(buf.Bytes(), &v)) && assert.Len(t, v, 3) {
+			assert.Equal(t, []interface{}{map[string]interface{}{
+				"id": "SmartFridge22334411",
+				"infoItems": []interface{}{map[string]interface{}{
+					"name":   "PowerConsumption",
+					"values": []interface{}{map[string]interface{}{"value": "43", "time": "2014-10-08T13:36:45Z"}},
+				}},
+			}}, v[0]["payload"])
+			assert.Equal(t, "<obj/>", v[1]["payload"])
+			assert.Equal(t, map[string]interface{}{"returnCode": "404", "description": "Not Found", "requestId": "REQ1"}, v[2])
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"strings"
+)
+
+func buildEnvelope(command string, args []string) (mi.OmiEnvelope, error) {
+	envelope := mi.OmiEnvelope{Version: "1.0", Ttl: *ttl}
+	if len(args) == 0 && command != "read" {
+		return envelope, fmt.Errorf("%s needs at least one argument", command)
+	}
+
+	switch command {
+	case "read", "subscribe":
+		message, err := odfMessage(query(args))
+		if err != nil {
+			return envelope, err
+		}
+		envelope.Read = &mi.ReadRequest{
+			MsgFormat: "odf",
+			Message:   message,
+			Newest:    *newest,
+			Oldest:    *oldest,
+			Begin:     *begin,
+			End:       *end,
+		}
+		if command == "subscribe" {
+			envelope.Read.Interval = *interval
+		}
+	case "write":
+		objects, err := values(args)
+		if err != nil {
+			return envelope, err
+		}
+		message, err := odfMessage(objects)
+		if err != nil {
+			return envelope, err
+		}
+		envelope.Write = &mi.WriteRequest{MsgFormat: "odf", Message: message}
+	case "poll":
+		envelope.Read = &mi.ReadRequest{RequestIds: requestIds(args)}
+	case "cancel":
+		envelope.Cancel = &mi.CancelRequest{RequestIds: requestIds(args)}
+	default:
+		return envelope, fmt.Errorf("unknown command %q", command)
+	}
+
+	return envelope, nil
+}
+
+// query names an Object for a PATH ending in a slash or with a single
+// element, since a top-level InfoItem cannot exist.
+func query(paths []string) df.Objects {
+	objects := df.Objects{}
+	for _, path := range paths {
+		p := df.ParsePath(path)
+		if strings.HasSuffix(path, "/") || len(p) == 1 {
+			objects.AddObject(p)
+		} else {
+			objects.Add(p)
+		}
+	}
+	return objects
+}
+
+func values(assignments []string) (df.Objects, error) {
+	objects := df.Objects{}
+	for _, assignment := range assignments {
+		i := strings.LastIndex(assignment, "=")
+		if i < 0 {
+			return objects, fmt.Errorf("%q is not of the form PATH=VALUE", assignment)
+		}
+		path := df.ParsePath(assignment[:i])
+		if len(path) < 2 {
+			return objects, fmt.Errorf("%q does not name an InfoItem", assignment[:i])
+		}
+		objects.Add(path, df.Value{Text: assignment[i+1:]})
+	}
+	return objects, nil
+}
+
+func requestIds(args []string) []mi.Id {
+	ids := make([]mi.Id, len(args))
+	for i, arg := range args {
+		ids[i] = mi.Id{Text: arg}
+	}
+	return ids
+}
+
+func odfMessage(objects df.Objects) (*mi.Message, error) {
+	return mi.EncodePayload("odf", objects)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/qlm-iot/qlm/df"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildReadEnvelope(t *testing.T) {
+	envelope, err := buildEnvelope("read", []string{"Objects/SmartFridge22334411/PowerConsumption", "Objects/Freezer/"})
+	if assert.Nil(t, err) && assert.NotNil(t, envelope.Read) {
+		assert.Equal(t, "odf", envelope.Read.MsgFormat)
+		assert.Equal(t, 0.0, envelope.Read.Interval)
+		objects, err := df.Unmarshal([]byte(envelope.Read.Message.Data))
+		if assert.Nil(t, err) {
+			assert.NotNil(t, objects.InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption")))
+			freezer := objects.Object(df.ParsePath("Freezer"))
+			if assert.NotNil(t, freezer) {
+				assert.Len(t, freezer.InfoItems, 0)
+			}
+		}
+	}
+}
+
+func TestBuildReadEnvelopeWithObjectPath(t *testing.T) {
+	envelope, err := buildEnvelope("read", []string{"SmartFridge22334411"})
+	if assert.Nil(t, err) && assert.NotNil(t, envelope.Read) {
+		objects, err := df.Unmarshal([]byte(envelope.Read.Message.Data))
+		if assert.Nil(t, err) && assert.Len(t, objects.Objects, 1) {
+			assert.Equal(t, "SmartFridge22334411", objects.Objects[0].ID())
+			assert.Len(t, objects.Objects[0].InfoItems, 0)
+		}
+	}
+}
+
+func TestBuildRootDiscoveryEnvelope(t *testing.T) {
+	envelope, err := buildEnvelope("read", nil)
+	if assert.Nil(t, err) && assert.NotNil(t, envelope.Read) {
+		objects, err := df.Unmarshal([]byte(envelope.Read.Message.Data))
+		if assert.Nil(t, err) {
+			assert.Len(t, objects.Objects, 0)
+		}
+	}
+
+	for _, command := range []string{"write", "subscribe", "poll", "cancel"} {
+		_, err = buildEnvelope(command, nil)
+		assert.NotNil(t, err, command)
+	}
+}
+
+func TestBuildSubscribeEnvelope(t *testing.T) {
+	envelope, err := buildEnvelope("subscribe", []string{"Objects/SmartFridge22334411/PowerConsumption"})
+	if assert.Nil(t, err) && assert.NotNil(t, envelope.Read) {
+		assert.Equal(t, -1.0, envelope.Read.Interval)
+	}
+}
+
+func TestBuildWriteEnvelope(t *testing.T) {
+	envelope, err := buildEnvelope("write", []string{"Objects/SmartFridge22334411/FridgeTemperatureSetpoint=3.5"})
+	if assert.Nil(t, err) && assert.NotNil(t, envelope.Write) {
+		objects, err := df.Unmarshal([]byte(envelope.Write.Message.Data))
+		if assert.Nil(t, err) {
+			item := objects.InfoItem(df.ParsePath("SmartFridge22334411/FridgeTemperatureSetpoint"))
+			if assert.NotNil(t, item) && assert.Len(t, item.Values, 1) {
+				assert.Equal(t, "3.5", item.Values[0].Text)
+			}
+		}
+	}
+
+	_, err = buildEnvelope("write", []string{"Objects/SmartFridge22334411/FridgeTemperatureSetpoint"})
+	assert.NotNil(t, err)
+	_, err = buildEnvelope("write", []string{"Objects/SmartFridge22334411=3.5"})
+	assert.NotNil(t, err)
+}
+
+func TestBuildPollAndCancelEnvelopes(t *testing.T) {
+	envelope, err := buildEnvelope("poll", []string{"REQ1", "REQ2"})
+	if assert.Nil(t, err) && assert.Len(t, envelope.Read.RequestIds, 2) {
+		assert.Equal(t, "REQ2", envelope.Read.RequestIds[1].Text)
+	}
+
+	envelope, err = buildEnvelope("cancel", []string{"REQ1"})
+	if assert.Nil(t, err) && assert.Len(t, envelope.Cancel.RequestIds, 1) {
+		assert.Equal(t, "REQ1", envelope.Cancel.RequestIds[0].Text)
+	}
+
+	_, err = buildEnvelope("delete", []string{"REQ1"})
+	assert.NotNil(t, err)
+}
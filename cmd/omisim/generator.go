@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/node"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleFlag maps InfoItem paths to the interval of their generated values.
+type scheduleFlag map[string]time.Duration
+
+func (s scheduleFlag) String() string {
+	parts := []string{}
+	for path, every := range s {
+		parts = append(parts, path+"="+every.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s scheduleFlag) Set(v string) error {
+	i := strings.LastIndex(v, "=")
+	if i < 0 {
+		return fmt.Errorf("%q is not of the form PATH=DURATION", v)
+	}
+	every, err := time.ParseDuration(v[i+1:])
+	if err != nil {
+		return err
+	}
+	s[df.ParsePath(v[:i]).String()] = every
+	return nil
+}
+
+type generator struct {
+	path  df.Path
+	every time.Duration
+	value float64
+}
+
+// generators returns a generator for every numeric InfoItem in objects.
+func generators(objects df.Objects, every time.Duration, schedule scheduleFlag) []*generator {
+	gens := []*generator{}
+	objects.Walk(func(path df.Path, item *df.InfoItem) {
+		g := &generator{path: path, every: every}
+		if len(item.Values) > 0 {
+			v, err := strconv.ParseFloat(strings.TrimSpace(item.Values[len(item.Values)-1].Text), 64)
+			if err != nil {
+				return
+			}
+			g.value = v
+		}
+		if scheduled, ok := schedule[path.String()]; ok {
+			g.every = scheduled
+		}
+		if g.every > 0 {
+			gens = append(gens, g)
+		}
+	})
+	return gens
+}
+
+// next drifts the value by up to two percent, or by up to 0.1 around zero.
+func (g *generator) next(rnd *rand.Rand) float64 {
+	step := math.Max(math.Abs(g.value)*0.02, 0.1)
+	g.value += step * (rnd.Float64()*2 - 1)
+	return g.value
+}
+
+func (g *generator) sample(now time.Time, rnd *rand.Rand) df.Value {
+	return df.Value{
+		Text:     strconv.FormatFloat(g.next(rnd), 'f', 2, 64),
+		DateTime: now.UTC().Format(time.RFC3339),
+		UnixTime: now.Unix(),
+	}
+}
+
+func (g *generator) run(n *node.Node, rnd *rand.Rand, stop <-chan struct{}) {
+	ticker := time.NewTicker(g.every)
+	defer ticker.Stop()
+	g.publish(n, rnd, ticker.C, stop)
+}
+
+// publish publishes a sample for every tick until stop is closed.
+func (g *generator) publish(n *node.Node, rnd *rand.Rand, ticks <-chan time.Time, stop <-chan struct{}) {
+	for {
+		select {
+		case now := <-ticks:
+			n.Publish(g.path, g.sample(now, rnd))
+		case <-stop:
+			return
+		}
+	}
+}
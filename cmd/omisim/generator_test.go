@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/node"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func loadObjects(t *testing.T, name string) df.Objects {
+	data, err := ioutil.ReadFile("../../df/examples/" + name)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	v, err := df.Unmarshal(data)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return *v
+}
+
+func TestGeneratorsForNumericInfoItems(t *testing.T) {
+	objects := loadObjects(t, "object_object_infoitem_values.xml")
+	schedule := scheduleFlag{}
+	assert.Nil(t, schedule.Set("Objects/UniqueTargetID_1/SubTarget2/SubTarget2InfoItem1=5s"))
+	assert.Nil(t, schedule.Set("Objects/UniqueTargetID_1/SubTarget1/SubInfoItem1=0"))
+
+	gens := generators(objects, time.Second, schedule)
+	if assert.Len(t, gens, 2) {
+		assert.Equal(t, "Objects/UniqueTargetID_1/SubTarget1/SubSubTarget1/SubSubTarget1InfoItem1", gens[0].path.String())
+		assert.Equal(t, time.Second, gens[0].every)
+		assert.Equal(t, 22.5, gens[0].value)
+		assert.Equal(t, "Objects/UniqueTargetID_1/SubTarget2/SubTarget2InfoItem1", gens[1].path.String())
+		assert.Equal(t, 5*time.Second, gens[1].every)
+	}
+}
+
+func TestScheduleFlagRejectsInvalidValues(t *testing.T) {
+	schedule := scheduleFlag{}
+	assert.NotNil(t, schedule.Set("Objects/Fridge/Power"))
+	assert.NotNil(t, schedule.Set("Objects/Fridge/Power=often"))
+}
+
+func TestGeneratorDrifts(t *testing.T) {
+	g := &generator{path: df.ParsePath("Fridge/Power"), value: 15.5}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		previous := g.value
+		v, err := strconv.ParseFloat(g.sample(time.Unix(0, 0), rnd).Text, 64)
+		if assert.Nil(t, err) {
+			assert.InDelta(t, previous, v, previous*0.02+0.01)
+		}
+	}
+}
+
+func TestSampleTimesAgree(t *testing.T) {
+	g := &generator{path: df.ParsePath("Fridge/Power"), value: 15.5}
+	now := time.Date(2016, 3, 2, 12, 0, 0, 0, time.FixedZone("EET", 2*60*60))
+	v := g.sample(now, rand.New(rand.NewSource(1)))
+	assert.Equal(t, "2016-03-02T10:00:00Z", v.DateTime)
+	assert.Equal(t, now.Unix(), v.UnixTime)
+	parsed, ok := df.Value{DateTime: v.DateTime}.Time()
+	if assert.True(t, ok) {
+		assert.True(t, parsed.Equal(now))
+	}
+}
+
+func TestGeneratorPublishesToNode(t *testing.T) {
+	objects := loadObjects(t, "measurement_values_for_refrigerator_power_consumption.xml")
+	n := node.New(objects)
+	gens := generators(objects, 10*time.Millisecond, scheduleFlag{})
+	if assert.Len(t, gens, 1) {
+		stored := n.Store.Objects()
+		before := len(stored.InfoItem(gens[0].path).Values)
+		ticks := make(chan time.Time)
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			gens[0].publish(n, rand.New(rand.NewSource(1)), ticks, stop)
+			close(done)
+		}()
+		for i := 0; i < 3; i++ {
+			ticks <- time.Unix(int64(1412775405+i), 0)
+		}
+		close(stop)
+		<-done
+		stored = n.Store.Objects()
+		values := stored.InfoItem(gens[0].path).Values
+		if assert.Len(t, values, before+3) {
+			assert.Equal(t, int64(1412775407), values[len(values)-1].UnixTime)
+		}
+	}
+}
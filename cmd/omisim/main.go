@@ -0,0 +1,79 @@
+// Command omisim serves a simulated O-MI node on localhost.
+//
+// Usage:
+//
+//	omisim [flags] FILE
+//
+// The node is loaded from the O-DF document in FILE. Every InfoItem whose
+// latest value is numeric, or which has no values yet, gets a new value on
+// its schedule, drifting randomly from the previous one. The node answers
+// reads, writes, poll subscriptions and cancels posted to its address.
+// Subscriptions must be event based (interval -1) and polled by requestId;
+// interval and callback subscriptions are answered with returnCode 501.
+// Subscriptions without a ttl stay until cancelled; each buffers at most
+// -maxbuffer bytes of values, dropping the oldest.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/node"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"time"
+)
+
+var (
+	addr      = flag.String("addr", "localhost:8080", "address to serve the node on")
+	interval  = flag.Duration("interval", time.Second, "default interval between generated values")
+	history   = flag.Int("history", 100, "number of values kept per InfoItem")
+	maxBuffer = flag.Int("maxbuffer", node.DefaultMaxBufferBytes, "approximate bytes of values buffered per subscription, 0 for no limit")
+	seed      = flag.Int64("seed", 0, "random seed, 0 for a time based one")
+	schedule  = scheduleFlag{}
+)
+
+func init() {
+	flag.Var(schedule, "schedule", "PATH=DURATION interval for one InfoItem, 0 to keep it constant (repeatable)")
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: omisim [flags] FILE\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+	}
+
+	data, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	objects, err := df.Unmarshal(data)
+	if err != nil {
+		log.Fatalf("%s: %v", flag.Arg(0), err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	n := node.New(*objects)
+	n.Store.MaxValues = *history
+	n.Subscriptions.MaxBytes = *maxBuffer
+	for i, g := range generators(*objects, *interval, schedule) {
+		go g.run(n, rand.New(rand.NewSource(*seed+int64(i))), nil)
+		log.Printf("generating %s every %s", g.path, g.every)
+	}
+
+	log.Printf("serving O-MI node on http://%s/", *addr)
+	log.Fatal(http.ListenAndServe(*addr, n))
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const indent = "    "
+
+// format returns the canonical form of an O-DF or O-MI document.
+func format(src []byte) ([]byte, error) {
+	prolog, root, err := scanProlog(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	switch root {
+	case "Objects":
+		objects, err := df.Unmarshal(src)
+		if err != nil {
+			return nil, err
+		}
+		body, err = df.Marshal(*objects)
+		if err != nil {
+			return nil, err
+		}
+	case "omiEnvelope":
+		envelope, err := mi.Unmarshal(src)
+		if err != nil {
+			return nil, err
+		}
+		formatMessages(envelope, 0)
+		body, err = mi.Marshal(*envelope)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("root element must be Objects or omiEnvelope")
+	}
+	body, err = keepAttrs(src, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	for _, comment := range prolog {
+		buf.WriteString("<!--" + comment + "-->\n")
+	}
+	buf.Write(body)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// scanProlog returns the comments before the root element and its name.
+func scanProlog(src []byte) ([]string, string, error) {
+	comments := []string{}
+	d := xml.NewDecoder(bytes.NewReader(src))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, "", errors.New("no root element")
+		} else if err != nil {
+			return nil, "", err
+		}
+		switch t := tok.(type) {
+		case xml.Comment:
+			comments = append(comments, string(t))
+		case xml.StartElement:
+			return comments, t.Name.Local, nil
+		}
+	}
+}
+
+// element is the root or a msg element of a document.
+type element struct {
+	attrs []xml.Attr
+	// end is the offset just past the element's start tag.
+	end int64
+}
+
+// scanElements returns the root and msg elements of a document by their
+// position in the tree.
+func scanElements(data []byte) (map[string]element, error) {
+	elements := map[string]element{}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	path := []string{""}
+	counts := []map[string]int{map[string]int{}}
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return elements, nil
+		} else if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			counts[len(counts)-1][name]++
+			key := path[len(path)-1] + "/" + name + "[" + strconv.Itoa(counts[len(counts)-1][name]) + "]"
+			if len(path) == 1 || name == "msg" {
+				elements[key] = element{attrs: t.Attr, end: d.InputOffset()}
+			}
+			path = append(path, key)
+			counts = append(counts, map[string]int{})
+		case xml.EndElement:
+			path = path[:len(path)-1]
+			counts = counts[:len(counts)-1]
+		}
+	}
+}
+
+func attrName(a xml.Attr) string {
+	if a.Name.Space == "" {
+		return a.Name.Local
+	}
+	return a.Name.Space + ":" + a.Name.Local
+}
+
+// keepAttrs copies the attributes of the root and msg elements of src that
+// are missing from their formatted form in res. These are mostly namespace
+// declarations and schema locations, which mi.Marshal drops but which the
+// payloads of msg elements depend on.
+func keepAttrs(src, res []byte) ([]byte, error) {
+	before, err := scanElements(src)
+	if err != nil {
+		return nil, err
+	}
+	after, err := scanElements(res)
+	if err != nil {
+		return nil, err
+	}
+
+	inserts := map[int64][]byte{}
+	for key, e := range after {
+		has := map[string]bool{}
+		for _, a := range e.attrs {
+			has[attrName(a)] = true
+		}
+		var buf bytes.Buffer
+		for _, a := range before[key].attrs {
+			if has[attrName(a)] {
+				continue
+			}
+			buf.WriteString(" " + attrName(a) + `="`)
+			xml.EscapeText(&buf, []byte(a.Value))
+			buf.WriteString(`"`)
+		}
+		if buf.Len() > 0 {
+			end := e.end - 1
+			if res[end-1] == '/' {
+				end--
+			}
+			inserts[end] = buf.Bytes()
+		}
+	}
+	if len(inserts) == 0 {
+		return res, nil
+	}
+
+	var out bytes.Buffer
+	for i := range res {
+		out.Write(inserts[int64(i)])
+		out.WriteByte(res[i])
+	}
+	return out.Bytes(), nil
+}
+
+// formatMessages reindents O-DF payloads so that they line up with the
+// envelope around them, which is nested depth levels deep. Other payloads
+// are left as they are.
+func formatMessages(envelope *mi.OmiEnvelope, depth int) {
+	if envelope.Read != nil {
+		formatMessage(envelope.Read.MsgFormat, envelope.Read.Message, depth+3)
+	}
+	if envelope.Write != nil {
+		formatMessage(envelope.Write.MsgFormat, envelope.Write.Message, depth+3)
+	}
+	if envelope.Response != nil {
+		for i := range envelope.Response.Results {
+			result := &envelope.Response.Results[i]
+			formatMessage(result.MsgFormat, result.Message, depth+4)
+			if result.OmiEnvelope != nil {
+				formatMessages(result.OmiEnvelope, depth+3)
+			}
+		}
+	}
+}
+
+func formatMessage(format string, message *mi.Message, depth int) {
+	if message == nil || (format != "" && format != "odf" && format != "omi.xsd") {
+		return
+	}
+	objects, err := df.Unmarshal([]byte(message.Data))
+	if err != nil {
+		return
+	}
+	data, err := df.Marshal(*objects)
+	if err != nil {
+		return
+	}
+	prefix := strings.Repeat(indent, depth)
+	lines := strings.Split(string(data), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	message.Data = "\n" + strings.Join(lines, "\n") + "\n" + strings.Repeat(indent, depth-1)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatObjects(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- A fridge. -->
+<Objects xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="odf.xsd">
+  <Object type="Fridge"><id>SmartFridge22334411</id>
+     <InfoItem name="PowerConsumption"><value>43</value></InfoItem>
+  </Object>
+</Objects>`
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- A fridge. -->
+<Objects xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="odf.xsd">
+    <Object type="Fridge">
+        <id>SmartFridge22334411</id>
+        <InfoItem name="PowerConsumption">
+            <value>43</value>
+        </InfoItem>
+    </Object>
+</Objects>
+`
+	actual, err := format([]byte(src))
+	if assert.Nil(t, err) {
+		assert.Equal(t, expected, string(actual))
+	}
+}
+
+func TestFormatEnvelope(t *testing.T) {
+	src := `<omiEnvelope ttl="10" version="1.0"><read msgformat="odf"><msg><Objects><Object><id>SmartFridge22334411</id></Object></Objects></msg></read></omiEnvelope>`
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<omiEnvelope version="1.0" ttl="10">
+    <read msgformat="odf">
+        <msg>
+            <Objects xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="odf.xsd">
+                <Object>
+                    <id>SmartFridge22334411</id>
+                </Object>
+            </Objects>
+        </msg>
+    </read>
+</omiEnvelope>
+`
+	actual, err := format([]byte(src))
+	if assert.Nil(t, err) {
+		assert.Equal(t, expected, string(actual))
+	}
+}
+
+func TestFormatKeepsNamespaces(t *testing.T) {
+	src := `<omi:omiEnvelope xmlns:omi="omi.xsd" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="omi.xsd" version="1.0" ttl="10">
+<omi:response><omi:result msgformat="obix"><omi:msg xsi:schemaLocation="http://obix.org/ns/schema/1.0/obix.xsd" xmlns="http://obix.org/ns/schema/1.0"><obj href="http://myhome/thermostat"/></omi:msg></omi:result>
+<omi:result msgformat="odf"><omi:msg xmlns="odf.xsd"><Objects/></omi:msg></omi:result></omi:response></omi:omiEnvelope>`
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<omiEnvelope version="1.0" ttl="10" xmlns:omi="omi.xsd" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="omi.xsd">
+    <response>
+        <result msgformat="obix">
+            <msg xsi:schemaLocation="http://obix.org/ns/schema/1.0/obix.xsd" xmlns="http://obix.org/ns/schema/1.0"><obj href="http://myhome/thermostat"/></msg>
+        </result>
+        <result msgformat="odf">
+            <msg xmlns="odf.xsd">
+                <Objects xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="odf.xsd"></Objects>
+            </msg>
+        </result>
+    </response>
+</omiEnvelope>
+`
+	actual, err := format([]byte(src))
+	if assert.Nil(t, err) {
+		assert.Equal(t, expected, string(actual))
+	}
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	dfFiles, _ := filepath.Glob("../../df/examples/*.xml")
+	miFiles, _ := filepath.Glob("../../mi/examples/*.xml")
+	for _, name := range append(dfFiles, miFiles...) {
+		src, err := ioutil.ReadFile(name)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		once, err := format(src)
+		if assert.Nil(t, err, name) {
+			twice, err := format(once)
+			if assert.Nil(t, err, name) {
+				assert.Equal(t, string(once), string(twice), name)
+			}
+		}
+	}
+}
+
+func TestFormatWithUnknownRoot(t *testing.T) {
+	_, err := format([]byte(`<Things/>`))
+	assert.NotNil(t, err)
+}
+
+func TestFormatResponse(t *testing.T) {
+	src := `<omiEnvelope ttl="10" version="1.0"><response><result msgformat="odf"><msg><Objects/></msg></result></response></omiEnvelope>`
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<omiEnvelope version="1.0" ttl="10">
+    <response>
+        <result msgformat="odf">
+            <msg>
+                <Objects xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="odf.xsd"></Objects>
+            </msg>
+        </result>
+    </response>
+</omiEnvelope>
+`
+	actual, err := format([]byte(src))
+	if assert.Nil(t, err) {
+		assert.Equal(t, expected, string(actual))
+	}
+}
@@ -0,0 +1,89 @@
+// Command qlmfmt validates and formats O-DF and O-MI documents.
+//
+// Usage:
+//
+//	qlmfmt [flags] FILE...
+//
+// Without flags the formatted documents are written to standard output.
+// Documents are rewritten with df.Marshal or mi.Marshal, so elements and
+// attributes come out in a canonical order and indentation. Comments before
+// the root element are kept; comments inside it and namespace prefixes are
+// not. Attributes of the root and msg elements that the rewrite would drop,
+// such as namespace declarations, are kept after the others.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose formatting differs from qlmfmt's")
+	write = flag.Bool("w", false, "write result to (source) file instead of stdout")
+	check = flag.Bool("check", false, "only report invalid or unformatted files and exit with status 1 if there are any")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: qlmfmt [flags] FILE...\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+
+	status := 0
+	for _, name := range flag.Args() {
+		if !processFile(name) {
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
+
+func processFile(name string) bool {
+	src, err := ioutil.ReadFile(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return false
+	}
+
+	if problems := validate(src); len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", name, p)
+		}
+		return false
+	}
+
+	res, err := format(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return false
+	}
+
+	changed := !bytes.Equal(src, res)
+	if *list || *check {
+		if changed {
+			fmt.Println(name)
+		}
+		return !(*check && changed)
+	}
+	if *write {
+		if changed {
+			if err := ioutil.WriteFile(name, res, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return false
+			}
+		}
+		return true
+	}
+	os.Stdout.Write(res)
+	return true
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type problem struct {
+	Line int
+	Msg  string
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%d: %s", p.Line, p.Msg)
+}
+
+type frame struct {
+	name  string
+	line  int
+	sawId bool
+}
+
+// validate checks the structure of an O-DF or O-MI document and reports
+// every problem with the line it was found on.
+func validate(src []byte) []problem {
+	problems := []problem{}
+	report := func(line int, format string, args ...interface{}) {
+		problems = append(problems, problem{line, fmt.Sprintf(format, args...)})
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(src))
+	stack := []*frame{}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+				report(syntaxErr.Line, "%s", syntaxErr.Msg)
+			} else {
+				line, _ := d.InputPos()
+				report(line, "%v", err)
+			}
+			return problems
+		}
+
+		line, _ := d.InputPos()
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 && t.Name.Local != "Objects" && t.Name.Local != "omiEnvelope" {
+				report(line, "root element must be Objects or omiEnvelope, not %s", t.Name.Local)
+			}
+			if t.Name.Local == "id" && len(stack) > 0 {
+				stack[len(stack)-1].sawId = true
+			}
+			validateElement(t, func(format string, args ...interface{}) {
+				report(line, format, args...)
+			})
+			stack = append(stack, &frame{name: t.Name.Local, line: line})
+		case xml.EndElement:
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top.name == "Object" && !top.sawId {
+				report(top.line, "Object without id")
+			}
+		}
+	}
+	return problems
+}
+
+var (
+	floatAttrs = map[string][]string{
+		"omiEnvelope": {"ttl"},
+		"read":        {"interval"},
+	}
+	intAttrs = map[string][]string{
+		"read":  {"oldest", "newest"},
+		"value": {"unixTime"},
+	}
+	requiredAttrs = map[string][]string{
+		"omiEnvelope": {"version", "ttl"},
+		"InfoItem":    {"name"},
+		"return":      {"returnCode"},
+	}
+)
+
+func validateElement(e xml.StartElement, report func(format string, args ...interface{})) {
+	attrs := map[string]string{}
+	for _, attr := range e.Attr {
+		attrs[attr.Name.Local] = attr.Value
+	}
+	for _, name := range requiredAttrs[e.Name.Local] {
+		if attrs[name] == "" {
+			report("%s without %s attribute", e.Name.Local, name)
+		}
+	}
+	for _, name := range floatAttrs[e.Name.Local] {
+		if v, ok := attrs[name]; ok && v != "" {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				report("%s attribute %s=%q is not a number", e.Name.Local, name, v)
+			}
+		}
+	}
+	for _, name := range intAttrs[e.Name.Local] {
+		if v, ok := attrs[name]; ok && v != "" {
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				report("%s attribute %s=%q is not an integer", e.Name.Local, name, v)
+			}
+		}
+	}
+}
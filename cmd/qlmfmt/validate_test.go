@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateExamples(t *testing.T) {
+	dfFiles, _ := filepath.Glob("../../df/examples/*.xml")
+	miFiles, _ := filepath.Glob("../../mi/examples/*.xml")
+	for _, name := range append(dfFiles, miFiles...) {
+		src, err := ioutil.ReadFile(name)
+		if assert.Nil(t, err) {
+			assert.Len(t, validate(src), 0, name)
+		}
+	}
+}
+
+func TestValidateReportsLines(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<omiEnvelope version="1.0" ttl="ten">
+    <read msgformat="odf" interval="x">
+        <msg>
+            <Objects>
+                <Object>
+                    <InfoItem>
+                        <value unixTime="yesterday">1</value>
+                    </InfoItem>
+                </Object>
+            </Objects>
+        </msg>
+    </read>
+</omiEnvelope>`
+	assert.Equal(t, []problem{
+		problem{2, `omiEnvelope attribute ttl="ten" is not a number`},
+		problem{3, `read attribute interval="x" is not a number`},
+		problem{7, `InfoItem without name attribute`},
+		problem{8, `value attribute unixTime="yesterday" is not an integer`},
+		problem{6, `Object without id`},
+	}, validate([]byte(src)))
+}
+
+func TestValidateReportsSyntaxErrors(t *testing.T) {
+	src := `<Objects>
+    <Object>
+</Objects>`
+	problems := validate([]byte(src))
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, 3, problems[0].Line)
+	}
+}
@@ -0,0 +1,33 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeepCopySharesNoMemory(t *testing.T) {
+	v := loadExample(t, "metadata_about_refrigerator_power_consumption.xml")
+	v.Objects[0].Description = &Description{Text: "Fridge"}
+	v.Objects[0].InfoItems[0].OtherNames = []string{"Power"}
+	v.Objects[0].Objects = []Object{Object{Id: &QLMID{Text: "Door"}}}
+
+	c := v.DeepCopy()
+	assert.Equal(t, v, c)
+
+	c.Objects[0].Id.Text = "changed"
+	c.Objects[0].Description.Text = "changed"
+	c.Objects[0].InfoItems[0].OtherNames[0] = "changed"
+	c.Objects[0].InfoItems[0].MetaData.InfoItems[0].Values[0].Text = "changed"
+	c.Objects[0].Objects[0].Id.Text = "changed"
+
+	assert.Equal(t, "SmartFridge22334411", v.Objects[0].Id.Text)
+	assert.Equal(t, "Fridge", v.Objects[0].Description.Text)
+	assert.Equal(t, "Power", v.Objects[0].InfoItems[0].OtherNames[0])
+	assert.Equal(t, "xs:double", v.Objects[0].InfoItems[0].MetaData.InfoItems[0].Values[0].Text)
+	assert.Equal(t, "Door", v.Objects[0].Objects[0].Id.Text)
+}
+
+func TestDeepCopyOfNil(t *testing.T) {
+	var o *Object
+	assert.Nil(t, o.DeepCopy())
+}
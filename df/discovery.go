@@ -0,0 +1,61 @@
+package df
+
+// DiscoveryOptions bound the size of a discovery tree.
+type DiscoveryOptions struct {
+	// Depth is the number of Object levels listed below the starting point.
+	// Objects on the last level are listed by id only. Zero lists the whole
+	// hierarchy.
+	Depth int
+	// Offset and Limit page through the child Objects of the starting point.
+	// A zero Limit lists all of them.
+	Offset int
+	Limit  int
+}
+
+// Discover returns the structure of the tree without values, descriptions
+// or metadata, as a node answers a read of an empty Objects element.
+func (o Objects) Discover(options DiscoveryOptions) Objects {
+	o.Objects = discoverObjects(page(o.Objects, options), 1, options.Depth)
+	return o
+}
+
+// Discover returns the structure below the Object, as a node answers a read
+// of an Object that only carries its id.
+func (o Object) Discover(options DiscoveryOptions) Object {
+	o.Objects = page(o.Objects, options)
+	return discoverObject(o, 0, options.Depth)
+}
+
+func discoverObjects(objects []Object, level, depth int) []Object {
+	if objects == nil {
+		return nil
+	}
+	discovered := make([]Object, len(objects))
+	for i := range objects {
+		discovered[i] = discoverObject(objects[i], level, depth)
+	}
+	return discovered
+}
+
+func discoverObject(o Object, level, depth int) Object {
+	discovered := Object{Type: o.Type, Udef: o.Udef, Id: o.Id, OtherIds: o.OtherIds}
+	if depth > 0 && level >= depth {
+		return discovered
+	}
+	discovered.InfoItems = projectInfoItems(o.InfoItems, StructureOnly)
+	discovered.Objects = discoverObjects(o.Objects, level+1, depth)
+	return discovered
+}
+
+func page(objects []Object, options DiscoveryOptions) []Object {
+	if options.Offset > 0 {
+		if options.Offset >= len(objects) {
+			return nil
+		}
+		objects = objects[options.Offset:]
+	}
+	if options.Limit > 0 && options.Limit < len(objects) {
+		objects = objects[:options.Limit]
+	}
+	return objects
+}
@@ -0,0 +1,56 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiscoverObjects(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+
+	discovered := v.Discover(DiscoveryOptions{Depth: 1})
+	if assert.Len(t, discovered.Objects, 1) {
+		assert.Equal(t, "UniqueTargetID_1", discovered.Objects[0].ID())
+		assert.Equal(t, "someType", discovered.Objects[0].Type)
+		assert.Len(t, discovered.Objects[0].InfoItems, 0)
+		assert.Len(t, discovered.Objects[0].Objects, 0)
+	}
+}
+
+func TestDiscoverWholeHierarchy(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+
+	discovered := v.Discover(DiscoveryOptions{})
+	item := discovered.InfoItem(ParsePath("UniqueTargetID_1/SubTarget1/SubSubTarget1/SubSubTarget1InfoItem1"))
+	if assert.NotNil(t, item) {
+		assert.Len(t, item.Values, 0)
+	}
+	assert.Len(t, v.InfoItem(ParsePath("UniqueTargetID_1/SubTarget1/SubSubTarget1/SubSubTarget1InfoItem1")).Values, 1)
+}
+
+func TestDiscoverObject(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+
+	discovered := v.Objects[0].Discover(DiscoveryOptions{Depth: 1})
+	if assert.Len(t, discovered.InfoItems, 2) {
+		assert.Equal(t, "InfoItem1", discovered.InfoItems[0].Name)
+		assert.Len(t, discovered.InfoItems[0].Values, 0)
+	}
+	if assert.Len(t, discovered.Objects, 2) {
+		assert.Equal(t, "SubTarget1", discovered.Objects[0].ID())
+		assert.Len(t, discovered.Objects[0].InfoItems, 0)
+		assert.Len(t, discovered.Objects[0].Objects, 0)
+	}
+}
+
+func TestDiscoverObjectWithPaging(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+
+	discovered := v.Objects[0].Discover(DiscoveryOptions{Depth: 1, Offset: 1, Limit: 1})
+	if assert.Len(t, discovered.Objects, 1) {
+		assert.Equal(t, "SubTarget2", discovered.Objects[0].ID())
+	}
+
+	discovered = v.Objects[0].Discover(DiscoveryOptions{Depth: 1, Offset: 2, Limit: 1})
+	assert.Len(t, discovered.Objects, 0)
+}
@@ -0,0 +1,362 @@
+package df
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type fieldTag struct {
+	name, udef, unit string
+}
+
+func parseTag(field reflect.StructField) (fieldTag, bool) {
+	tag := fieldTag{name: field.Name}
+	s, ok := field.Tag.Lookup("odf")
+	if !ok {
+		return tag, true
+	}
+	parts := strings.Split(s, ",")
+	if parts[0] == "-" {
+		return tag, false
+	}
+	if parts[0] != "" {
+		tag.name = parts[0]
+	}
+	for _, part := range parts[1:] {
+		switch {
+		case strings.HasPrefix(part, "udef="):
+			tag.udef = strings.TrimPrefix(part, "udef=")
+		case strings.HasPrefix(part, "unit="):
+			tag.unit = strings.TrimPrefix(part, "unit=")
+		}
+	}
+	return tag, true
+}
+
+// Encode converts v, a struct or a pointer to one, to an Object with the
+// given id. Every exported field is mapped according to its odf struct tag:
+//
+//	Field T `odf:"name,udef=...,unit=..."`
+//
+// The name defaults to the field name and "-" skips the field; udef sets
+// the udef attribute and unit the unit in the InfoItem's MetaData, which
+// Decode does not check. Fields of basic types and time.Time become
+// InfoItems with a single value, slices of them InfoItems with one value
+// per element and slices of samples, structs with one exported time.Time
+// field and one exported field of a basic type, InfoItems with timestamped
+// values. Structs become child Objects whose id is the name, and embedded
+// structs without a tag are flattened. Nil pointers are skipped by Encode
+// and pointers are allocated by Decode when the Object has a value for
+// them.
+func Encode(id string, v interface{}) (Object, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return Object{}, fmt.Errorf("df: cannot encode %T as an Object", v)
+	}
+	object := Object{Id: &QLMID{Text: id}}
+	if err := encodeStruct(&object, rv); err != nil {
+		return Object{}, err
+	}
+	return object, nil
+}
+
+func encodeStruct(object *Object, rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag, ok := parseTag(field)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		_, tagged := field.Tag.Lookup("odf")
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if err := encodeStruct(object, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if err := encodeField(object, tag, fv); err != nil {
+			return fmt.Errorf("df: encoding %s: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func encodeField(object *Object, tag fieldTag, fv reflect.Value) error {
+	ft := fv.Type()
+	switch {
+	case ft.Kind() == reflect.Ptr:
+		if fv.IsNil() {
+			return nil
+		}
+		return encodeField(object, tag, fv.Elem())
+	case ft.Kind() == reflect.Struct && ft != timeType:
+		child := Object{Udef: tag.udef, Id: &QLMID{Text: tag.name}}
+		if err := encodeStruct(&child, fv); err != nil {
+			return err
+		}
+		object.Objects = append(object.Objects, child)
+		return nil
+	}
+
+	item := InfoItem{Name: tag.name, Udef: tag.udef}
+	if tag.unit != "" {
+		item.MetaData = Meta{Unit: tag.unit}.MetaData()
+	}
+	switch {
+	case ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8:
+		sample, isSample := sampleFields(ft.Elem())
+		for i := 0; i < fv.Len(); i++ {
+			elem := fv.Index(i)
+			if isSample {
+				value, err := encodeValue(elem.Field(sample.value))
+				if err != nil {
+					return err
+				}
+				value.DateTime = elem.Field(sample.time).Interface().(time.Time).Format(time.RFC3339Nano)
+				item.Values = append(item.Values, value)
+				continue
+			}
+			value, err := encodeValue(elem)
+			if err != nil {
+				return err
+			}
+			item.Values = append(item.Values, value)
+		}
+	default:
+		value, err := encodeValue(fv)
+		if err != nil {
+			return err
+		}
+		item.Values = []Value{value}
+	}
+	object.InfoItems = append(object.InfoItems, item)
+	return nil
+}
+
+type sample struct {
+	time, value int
+}
+
+// sampleFields finds the time and value fields of a sample struct. Both
+// must be exported.
+func sampleFields(t reflect.Type) (sample, bool) {
+	if t.Kind() != reflect.Struct || t == timeType || t.NumField() != 2 {
+		return sample{}, false
+	}
+	if t.Field(0).PkgPath != "" || t.Field(1).PkgPath != "" {
+		return sample{}, false
+	}
+	for i, j := 0, 1; i < 2; i, j = i+1, j-1 {
+		if t.Field(i).Type == timeType && valueType(t.Field(j).Type) != "" && t.Field(j).Type != timeType {
+			return sample{time: i, value: j}, true
+		}
+	}
+	return sample{}, false
+}
+
+func valueType(t reflect.Type) string {
+	if t == timeType {
+		return "xs:dateTime"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "xs:string"
+	case reflect.Bool:
+		return "xs:boolean"
+	case reflect.Int, reflect.Int64:
+		return "xs:long"
+	case reflect.Int32:
+		return "xs:int"
+	case reflect.Int16:
+		return "xs:short"
+	case reflect.Int8:
+		return "xs:byte"
+	case reflect.Uint, reflect.Uint64:
+		return "xs:unsignedLong"
+	case reflect.Uint32:
+		return "xs:unsignedInt"
+	case reflect.Uint16:
+		return "xs:unsignedShort"
+	case reflect.Uint8:
+		return "xs:unsignedByte"
+	case reflect.Float64:
+		return "xs:double"
+	case reflect.Float32:
+		return "xs:float"
+	}
+	return ""
+}
+
+func encodeValue(v reflect.Value) (Value, error) {
+	value := Value{Type: valueType(v.Type())}
+	if v.Type() == timeType {
+		value.Text = v.Interface().(time.Time).Format(time.RFC3339Nano)
+		return value, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		value.Text = v.String()
+	case reflect.Bool:
+		value.Text = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.Text = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.Text = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		value.Text = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	default:
+		return Value{}, fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return value, nil
+}
+
+// Decode fills v, a pointer to a struct, from object as described for
+// Encode. Fields without a matching InfoItem or child Object are left as
+// they are; single valued fields take the latest value.
+func Decode(object Object, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("df: cannot decode an Object into %T", v)
+	}
+	return decodeStruct(&object, rv.Elem())
+}
+
+func decodeStruct(object *Object, rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag, ok := parseTag(field)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		_, tagged := field.Tag.Lookup("odf")
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(object, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if err := decodeField(object, tag, fv); err != nil {
+			return fmt.Errorf("df: decoding %s: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func decodeField(object *Object, tag fieldTag, fv reflect.Value) error {
+	ft := fv.Type()
+	switch {
+	case ft.Kind() == reflect.Ptr:
+		if ft.Elem().Kind() == reflect.Struct && ft.Elem() != timeType {
+			if findChild(object.Objects, tag.name, nil) == nil {
+				return nil
+			}
+		} else if item := object.InfoItem(tag.name); item == nil || len(item.Values) == 0 {
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(ft.Elem()))
+		}
+		return decodeField(object, tag, fv.Elem())
+	case ft.Kind() == reflect.Struct && ft != timeType:
+		child := findChild(object.Objects, tag.name, nil)
+		if child == nil {
+			return nil
+		}
+		return decodeStruct(child, fv)
+	}
+
+	item := object.InfoItem(tag.name)
+	if item == nil || len(item.Values) == 0 {
+		return nil
+	}
+	if ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8 {
+		sample, isSample := sampleFields(ft.Elem())
+		slice := reflect.MakeSlice(ft, len(item.Values), len(item.Values))
+		for i, value := range item.Values {
+			elem := slice.Index(i)
+			if isSample {
+				t, ok := value.Time()
+				if !ok {
+					return fmt.Errorf("value %d of %s has no timestamp", i, item.Name)
+				}
+				elem.Field(sample.time).Set(reflect.ValueOf(t))
+				elem = elem.Field(sample.value)
+			}
+			if err := decodeValue(value.Text, elem); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+	latest, _ := latestValue(item.Values)
+	return decodeValue(latest.Text, fv)
+}
+
+// decodeValue sets v from text. Strings are kept as they are, surrounding
+// space is ignored for other types.
+func decodeValue(text string, v reflect.Value) error {
+	if v.Kind() != reflect.String {
+		text = strings.TrimSpace(text)
+	}
+	if v.Type() == timeType {
+		t, ok := parseDateTime(text)
+		if !ok {
+			return fmt.Errorf("invalid dateTime %q", text)
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
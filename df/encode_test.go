@@ -0,0 +1,171 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type reading struct {
+	At    time.Time
+	Value float64
+}
+
+type compressor struct {
+	Running bool
+	Starts  uint32 `odf:"StartCount"`
+}
+
+type fridge struct {
+	Model       string    `odf:"ModelName,udef=mu3"`
+	Temperature float32   `odf:",unit=°C"`
+	Power       []reading `odf:"PowerConsumption,unit=W"`
+	Doors       []int
+	Serviced    time.Time
+	Compressor  compressor `odf:",udef=dev"`
+	Light       *compressor
+	Setpoint    *float64
+	Installed   *time.Time
+	Ignored     string `odf:"-"`
+	internal    string
+}
+
+func TestEncode(t *testing.T) {
+	serviced := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	f := fridge{
+		Model:       "F-22",
+		Temperature: 4.5,
+		Power: []reading{
+			reading{date("2016-03-02T10:00:00Z"), 120},
+			reading{date("2016-03-02T10:05:00Z"), 95.5},
+		},
+		Doors:      []int{1, 2},
+		Serviced:   serviced,
+		Compressor: compressor{Running: true, Starts: 42},
+		Ignored:    "x",
+		internal:   "y",
+	}
+	object, err := Encode("SmartFridge", &f)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "SmartFridge", object.Id.Text)
+		assert.Equal(t, []InfoItem{
+			InfoItem{Name: "ModelName", Udef: "mu3", Values: []Value{Value{Text: "F-22", Type: "xs:string"}}},
+			InfoItem{Name: "Temperature", MetaData: Meta{Unit: "°C"}.MetaData(), Values: []Value{Value{Text: "4.5", Type: "xs:float"}}},
+			InfoItem{Name: "PowerConsumption", MetaData: Meta{Unit: "W"}.MetaData(), Values: []Value{
+				Value{Text: "120", Type: "xs:double", DateTime: "2016-03-02T10:00:00Z"},
+				Value{Text: "95.5", Type: "xs:double", DateTime: "2016-03-02T10:05:00Z"},
+			}},
+			InfoItem{Name: "Doors", Values: []Value{Value{Text: "1", Type: "xs:long"}, Value{Text: "2", Type: "xs:long"}}},
+			InfoItem{Name: "Serviced", Values: []Value{Value{Text: "2016-03-01T12:00:00Z", Type: "xs:dateTime"}}},
+		}, object.InfoItems)
+		if assert.Len(t, object.Objects, 1) {
+			assert.Equal(t, Object{Udef: "dev", Id: &QLMID{Text: "Compressor"}, InfoItems: []InfoItem{
+				InfoItem{Name: "Running", Values: []Value{Value{Text: "true", Type: "xs:boolean"}}},
+				InfoItem{Name: "StartCount", Values: []Value{Value{Text: "42", Type: "xs:unsignedInt"}}},
+			}}, object.Objects[0])
+		}
+	}
+}
+
+func TestEncodeRejectsNonStruct(t *testing.T) {
+	_, err := Encode("x", 42)
+	assert.NotNil(t, err)
+	_, err = Encode("x", struct{ C chan int }{})
+	assert.NotNil(t, err)
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	setpoint, installed := 3.5, date("2015-11-20T08:00:00Z")
+	f := fridge{
+		Model:       "F-22",
+		Temperature: 4.5,
+		Power:       []reading{reading{date("2016-03-02T10:00:00Z"), 120}},
+		Doors:       []int{3},
+		Serviced:    date("2016-03-01T12:00:00Z"),
+		Compressor:  compressor{Running: true, Starts: 42},
+		Light:       &compressor{Starts: 7},
+		Setpoint:    &setpoint,
+		Installed:   &installed,
+	}
+	object, err := Encode("SmartFridge", f)
+	if assert.Nil(t, err) {
+		data, err := Marshal(Objects{Objects: []Object{object}})
+		if assert.Nil(t, err) {
+			objects, err := Unmarshal(data)
+			if assert.Nil(t, err) {
+				var decoded fridge
+				if assert.Nil(t, Decode(objects.Objects[0], &decoded)) {
+					assert.Equal(t, f, decoded)
+				}
+			}
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	object := Object{Id: &QLMID{Text: "SmartFridge"}, InfoItems: []InfoItem{
+		InfoItem{Name: "ModelName", Values: []Value{Value{Text: " F-22 "}}},
+		InfoItem{Name: "Temperature", Values: []Value{
+			Value{Text: "5", UnixTime: 1456920000},
+			Value{Text: "3.5", UnixTime: 1456920300},
+			Value{Text: "4", UnixTime: 1456920100},
+		}},
+		InfoItem{Name: "PowerConsumption", Values: []Value{
+			Value{Text: "120", UnixTime: 1456920000},
+		}},
+	}}
+	f := fridge{Ignored: "kept", Doors: []int{9}}
+	if assert.Nil(t, Decode(object, &f)) {
+		assert.Equal(t, " F-22 ", f.Model)
+		assert.Equal(t, float32(3.5), f.Temperature)
+		assert.Equal(t, []reading{reading{time.Unix(1456920000, 0).UTC(), 120}}, f.Power)
+		assert.Equal(t, []int{9}, f.Doors)
+		assert.Equal(t, "kept", f.Ignored)
+		assert.Nil(t, f.Light)
+		assert.Nil(t, f.Setpoint)
+		assert.Nil(t, f.Installed)
+	}
+}
+
+func TestDecodePointers(t *testing.T) {
+	object := Object{Id: &QLMID{Text: "SmartFridge"}, InfoItems: []InfoItem{
+		InfoItem{Name: "Setpoint", Values: []Value{Value{Text: " 4.5 "}}},
+	}}
+	var f fridge
+	if assert.Nil(t, Decode(object, &f)) && assert.NotNil(t, f.Setpoint) {
+		assert.Equal(t, 4.5, *f.Setpoint)
+		assert.Nil(t, f.Installed)
+	}
+}
+
+func TestUnexportedSampleFields(t *testing.T) {
+	type log struct {
+		Readings []struct {
+			at time.Time
+			v  float64
+		}
+	}
+	l := log{}
+	l.Readings = append(l.Readings, struct {
+		at time.Time
+		v  float64
+	}{date("2016-03-02T10:00:00Z"), 120})
+	_, err := Encode("Log", l)
+	assert.NotNil(t, err)
+
+	object := Object{InfoItems: []InfoItem{
+		InfoItem{Name: "Readings", Values: []Value{Value{Text: "120", UnixTime: 1456920000}}},
+	}}
+	assert.NotNil(t, Decode(object, &l))
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var f fridge
+	assert.NotNil(t, Decode(Object{}, f))
+	assert.NotNil(t, Decode(Object{InfoItems: []InfoItem{
+		InfoItem{Name: "Doors", Values: []Value{Value{Text: "open"}}},
+	}}, &f))
+	assert.NotNil(t, Decode(Object{InfoItems: []InfoItem{
+		InfoItem{Name: "PowerConsumption", Values: []Value{Value{Text: "1"}}},
+	}}, &f))
+}
@@ -0,0 +1,214 @@
+package df
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+)
+
+// Hash is a SHA-256 digest of the canonical form of a node in a tree.
+type Hash [sha256.Size]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// HashTree mirrors the Objects of a tree with their hashes. The hash of an
+// Object covers its whole subtree, so two trees differ below an Object only
+// if its hashes differ.
+type HashTree struct {
+	Hash Hash
+	// Self covers the Object's own id, attributes and description but not
+	// its InfoItems or child Objects.
+	Self      Hash
+	Id        string
+	InfoItems map[string]Hash
+	Objects   []*HashTree
+}
+
+// Sum returns the hash of a whole tree.
+func Sum(objects Objects) Hash {
+	return Hashes(objects).Hash
+}
+
+// Hashes returns the hash tree of objects, computed over Normalize(objects).
+func Hashes(objects Objects) *HashTree {
+	normalized := Normalize(objects)
+	root := &HashTree{InfoItems: map[string]Hash{}}
+	h := sha256.New()
+	writeString(h, "Objects")
+	writeString(h, normalized.Version)
+	root.Self = sum(h)
+	for _, o := range normalized.Objects {
+		child := hashObject(o)
+		root.Objects = append(root.Objects, child)
+		h.Write(child.Hash[:])
+	}
+	root.Hash = sum(h)
+	return root
+}
+
+// HashObject returns the hash of an Object and its subtree.
+func HashObject(o Object) Hash {
+	return hashObject(Normalize(Objects{Objects: []Object{o}}).Objects[0]).Hash
+}
+
+// HashInfoItem returns the hash of an InfoItem with its values and metadata.
+func HashInfoItem(item InfoItem) Hash {
+	normalized := normalizeInfoItems([]InfoItem{item})
+	return hashInfoItem(normalized[0])
+}
+
+// Diff returns the paths of the Objects and InfoItems that have to be
+// fetched to bring t up to date with other. Subtrees whose hashes match are
+// skipped; an Object whose own attributes differ is returned as a whole.
+func (t *HashTree) Diff(other *HashTree) []Path {
+	return diff(Path{}, t, other)
+}
+
+func diff(path Path, a, b *HashTree) []Path {
+	if a.Hash == b.Hash {
+		return nil
+	}
+	if a.Self != b.Self {
+		return []Path{path}
+	}
+
+	paths := []Path{}
+	for name, h := range b.InfoItems {
+		if a.InfoItems[name] != h {
+			paths = append(paths, path.Child(name))
+		}
+	}
+	for name := range a.InfoItems {
+		if _, ok := b.InfoItems[name]; !ok {
+			paths = append(paths, path.Child(name))
+		}
+	}
+	sortPaths(paths)
+
+	children := map[string]*HashTree{}
+	for _, child := range a.Objects {
+		children[child.Id] = child
+	}
+	seen := map[string]bool{}
+	for _, child := range b.Objects {
+		seen[child.Id] = true
+		if mine, ok := children[child.Id]; ok {
+			paths = append(paths, diff(path.Child(child.Id), mine, child)...)
+		} else {
+			paths = append(paths, path.Child(child.Id))
+		}
+	}
+	for _, child := range a.Objects {
+		if !seen[child.Id] {
+			paths = append(paths, path.Child(child.Id))
+		}
+	}
+	return paths
+}
+
+func hashObject(o Object) *HashTree {
+	tree := &HashTree{Id: o.ID(), InfoItems: map[string]Hash{}}
+
+	h := sha256.New()
+	writeString(h, "Object")
+	writeString(h, o.Type)
+	writeString(h, o.Udef)
+	writeInt(h, int64(len(o.Ids())))
+	for _, id := range o.Ids() {
+		writeId(h, &id)
+	}
+	writeInt(h, int64(len(o.Descriptions())))
+	for _, description := range o.Descriptions() {
+		writeDescription(h, &description)
+	}
+	tree.Self = sum(h)
+
+	for _, item := range o.InfoItems {
+		itemHash := hashInfoItem(item)
+		tree.InfoItems[item.Name] = itemHash
+		h.Write(itemHash[:])
+	}
+	for _, child := range o.Objects {
+		childTree := hashObject(child)
+		tree.Objects = append(tree.Objects, childTree)
+		h.Write(childTree.Hash[:])
+	}
+	tree.Hash = sum(h)
+	return tree
+}
+
+func hashInfoItem(item InfoItem) Hash {
+	h := sha256.New()
+	writeString(h, "InfoItem")
+	writeString(h, item.Name)
+	writeString(h, item.Udef)
+	writeDescription(h, item.Description)
+	writeInt(h, int64(len(item.OtherNames)))
+	for _, name := range item.OtherNames {
+		writeString(h, name)
+	}
+	if item.MetaData != nil {
+		writeString(h, "MetaData")
+		for _, meta := range item.MetaData.InfoItems {
+			metaHash := hashInfoItem(meta)
+			h.Write(metaHash[:])
+		}
+	}
+	writeInt(h, int64(len(item.Values)))
+	for _, v := range item.Values {
+		writeString(h, v.Text)
+		writeString(h, v.Type)
+		writeString(h, v.DateTime)
+		writeInt(h, v.UnixTime)
+	}
+	return sum(h)
+}
+
+func writeId(h hash.Hash, id *QLMID) {
+	if id == nil {
+		writeString(h, "")
+		return
+	}
+	writeString(h, "id")
+	for _, s := range []string{id.IdType, id.TagType, id.StartDate, id.EndDate, id.Udef, id.Text} {
+		writeString(h, s)
+	}
+}
+
+func writeDescription(h hash.Hash, description *Description) {
+	if description == nil {
+		writeString(h, "")
+		return
+	}
+	writeString(h, "description")
+	writeString(h, description.Lang)
+	writeString(h, description.Udef)
+	writeString(h, description.Text)
+}
+
+// writeString length-prefixes s so that adjacent fields cannot run together.
+func writeString(h hash.Hash, s string) {
+	writeInt(h, int64(len(s)))
+	h.Write([]byte(s))
+}
+
+func writeInt(h hash.Hash, n int64) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutVarint(buf[:], n)])
+}
+
+func sum(h hash.Hash) Hash {
+	var sum Hash
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+func sortPaths(paths []Path) {
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].String() < paths[j].String()
+	})
+}
@@ -0,0 +1,48 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSumIsStableAcrossOrder(t *testing.T) {
+	a := Objects{Objects: []Object{
+		Object{Id: &QLMID{Text: "A"}, InfoItems: []InfoItem{InfoItem{Name: "x"}, InfoItem{Name: "y"}}},
+		Object{Id: &QLMID{Text: "B"}},
+	}}
+	b := Objects{Objects: []Object{
+		Object{Id: &QLMID{Text: "B "}},
+		Object{Id: &QLMID{Text: "A"}, InfoItems: []InfoItem{InfoItem{Name: "y"}, InfoItem{Name: "x"}}},
+	}}
+	assert.Equal(t, Sum(a), Sum(b))
+	assert.Equal(t, HashObject(a.Objects[0]), HashObject(b.Objects[1]))
+
+	b.Objects[1].InfoItems[0].Values = []Value{Value{Text: "1"}}
+	assert.NotEqual(t, Sum(a), Sum(b))
+	assert.NotEqual(t, HashObject(a.Objects[0]), HashObject(b.Objects[1]))
+	assert.Equal(t, HashObject(a.Objects[1]), HashObject(b.Objects[0]))
+}
+
+func TestHashInfoItemSeparatesFields(t *testing.T) {
+	assert.NotEqual(t,
+		HashInfoItem(InfoItem{Name: "ab", Udef: "c"}),
+		HashInfoItem(InfoItem{Name: "a", Udef: "bc"}))
+	assert.Len(t, HashInfoItem(InfoItem{Name: "a"}).String(), 64)
+}
+
+func TestHashTreeDiff(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+	changed := Normalize(*v)
+	changed.Add(ParsePath("UniqueTargetID_1/SubTarget1/SubSubTarget1/SubSubTarget1InfoItem1"), Value{Text: "23.0"})
+	changed.Add(ParsePath("UniqueTargetID_1/SubTarget3/New"))
+	changed.Object(ParsePath("UniqueTargetID_1/SubTarget2")).Type = "otherType"
+
+	paths := Hashes(*v).Diff(Hashes(changed))
+	assert.Equal(t, []Path{
+		ParsePath("UniqueTargetID_1/SubTarget1/SubSubTarget1/SubSubTarget1InfoItem1"),
+		ParsePath("UniqueTargetID_1/SubTarget2"),
+		ParsePath("UniqueTargetID_1/SubTarget3"),
+	}, paths)
+
+	assert.Len(t, Hashes(*v).Diff(Hashes(*v)), 0)
+}
@@ -0,0 +1,94 @@
+package df
+
+import (
+	"strings"
+	"time"
+)
+
+// Start returns the time from which the id is valid, if it has a startDate.
+func (id QLMID) Start() (time.Time, bool) {
+	return parseDateTime(id.StartDate)
+}
+
+// End returns the time until which the id is valid, if it has an endDate.
+func (id QLMID) End() (time.Time, bool) {
+	return parseDateTime(id.EndDate)
+}
+
+// ValidAt reports whether t falls between the id's startDate and endDate.
+// A missing or unparseable date leaves that end of the range open.
+func (id QLMID) ValidAt(t time.Time) bool {
+	if start, ok := id.Start(); ok && t.Before(start) {
+		return false
+	}
+	if end, ok := id.End(); ok && t.After(end) {
+		return false
+	}
+	return true
+}
+
+// ActiveId returns the id among ids that is valid at t and started most
+// recently, or nil if none is valid. When idType is not empty only ids of
+// that type are considered.
+func ActiveId(ids []QLMID, t time.Time, idType string) *QLMID {
+	var active *QLMID
+	var activeStart time.Time
+	for i := range ids {
+		id := &ids[i]
+		if idType != "" && id.IdType != idType || !id.ValidAt(t) {
+			continue
+		}
+		start, _ := id.Start()
+		if active == nil || start.After(activeStart) {
+			active, activeStart = id, start
+		}
+	}
+	return active
+}
+
+// Ids returns every id of the Object, e.g. a serial number and an EPC tag.
+func (o *Object) Ids() []QLMID {
+	if o.Id == nil {
+		return nil
+	}
+	return append([]QLMID{*o.Id}, o.OtherIds...)
+}
+
+// IdAt returns the Object's id of the given idType that is valid at t, or
+// its valid id that started most recently when idType is empty.
+func (o *Object) IdAt(t time.Time, idType string) *QLMID {
+	return ActiveId(o.Ids(), t, idType)
+}
+
+// HasId reports whether any of the Object's ids is text. When at is not
+// nil only ids valid at that time count.
+func (o *Object) HasId(text string, at *time.Time) bool {
+	if text == o.ID() && at == nil {
+		return true
+	}
+	for _, id := range o.Ids() {
+		if strings.TrimSpace(id.Text) == text && (at == nil || id.ValidAt(*at)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectAt returns the Object at path like Object, but matches each Object
+// by any of its ids that is valid at t.
+func (o *Objects) ObjectAt(path Path, t time.Time) *Object {
+	return o.lookup(path, &t)
+}
+
+// InfoItemAt returns the InfoItem at path like InfoItem, but matches each
+// Object by any of its ids that is valid at t.
+func (o *Objects) InfoItemAt(path Path, t time.Time) *InfoItem {
+	if len(path) < 2 {
+		return nil
+	}
+	parent := o.lookup(path[:len(path)-1], &t)
+	if parent == nil {
+		return nil
+	}
+	return parent.InfoItem(path[len(path)-1])
+}
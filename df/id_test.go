@@ -0,0 +1,58 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, _ := parseDateTime(s)
+	return t
+}
+
+func TestQLMIDValidAt(t *testing.T) {
+	id := QLMID{StartDate: "2013-10-26T21:32:52", EndDate: "2015-10-26T21:32:52", Text: "SmartFridge22334411"}
+	assert.False(t, id.ValidAt(date("2013-10-26T21:32:51")))
+	assert.True(t, id.ValidAt(date("2013-10-26T21:32:52")))
+	assert.True(t, id.ValidAt(date("2014-06-01T00:00")))
+	assert.False(t, id.ValidAt(date("2015-10-26T21:32:53")))
+
+	assert.True(t, QLMID{Text: "Forever"}.ValidAt(date("1970-01-01T00:00")))
+	assert.True(t, QLMID{EndDate: "2015-10-26T21:32:52"}.ValidAt(date("1970-01-01T00:00")))
+}
+
+func TestActiveId(t *testing.T) {
+	ids := []QLMID{
+		QLMID{IdType: "serial", Text: "SN-1"},
+		QLMID{IdType: "epc", EndDate: "2014-01-01T00:00", Text: "urn:epc:id:sgtin:1"},
+		QLMID{IdType: "epc", StartDate: "2014-01-01T00:00", Text: "urn:epc:id:sgtin:2"},
+	}
+
+	assert.Equal(t, "urn:epc:id:sgtin:1", ActiveId(ids, date("2013-06-01T00:00"), "epc").Text)
+	assert.Equal(t, "urn:epc:id:sgtin:2", ActiveId(ids, date("2014-06-01T00:00"), "epc").Text)
+	assert.Equal(t, "SN-1", ActiveId(ids, date("2014-06-01T00:00"), "serial").Text)
+	assert.Equal(t, "urn:epc:id:sgtin:2", ActiveId(ids, date("2014-06-01T00:00"), "").Text)
+	assert.Equal(t, "SN-1", ActiveId(ids, date("2013-06-01T00:00"), "").Text)
+	assert.Nil(t, ActiveId(ids, date("2014-06-01T00:00"), "barcode"))
+}
+
+func TestObjectAt(t *testing.T) {
+	objects := Objects{Objects: []Object{
+		Object{
+			Id:        &QLMID{StartDate: "2014-01-01T00:00", EndDate: "2015-01-01T00:00", Text: "Tag1"},
+			InfoItems: []InfoItem{InfoItem{Name: "Power"}},
+		},
+	}}
+
+	assert.NotNil(t, objects.ObjectAt(ParsePath("Tag1"), date("2014-06-01T00:00")))
+	assert.Nil(t, objects.ObjectAt(ParsePath("Tag1"), date("2015-06-01T00:00")))
+	assert.NotNil(t, objects.InfoItemAt(ParsePath("Tag1/Power"), date("2014-06-01T00:00")))
+	assert.Nil(t, objects.InfoItemAt(ParsePath("Tag1/Power"), date("2013-06-01T00:00")))
+	assert.NotNil(t, objects.Object(ParsePath("Tag1")))
+
+	id := objects.Objects[0].IdAt(date("2014-06-01T00:00"), "")
+	if assert.NotNil(t, id) {
+		assert.Equal(t, "Tag1", id.Text)
+	}
+}
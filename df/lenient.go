@@ -0,0 +1,266 @@
+package df
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/qlm-iot/qlm/internal/xmlscan"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Warning describes a fragment that UnmarshalLenient skipped or coerced.
+// Path is the O-DF path of the enclosing Objects and InfoItems and Err a
+// *DecodeError locating the fragment in the document.
+type Warning struct {
+	Path Path
+	Err  error
+}
+
+func (w Warning) String() string {
+	return w.Path.String() + ": " + w.Err.Error()
+}
+
+var knownAttrs = map[string][]string{
+	"Objects":     {"version"},
+	"Object":      {"type", "udef"},
+	"id":          {"idType", "tagType", "startDate", "endDate", "udef"},
+	"description": {"lang", "udef"},
+	"InfoItem":    {"name", "udef"},
+	"value":       {"type", "dateTime", "unixTime"},
+}
+
+// UnmarshalLenient decodes an O-DF document within DefaultLimits, repairing
+// what it can; see Decoder.UnmarshalLenient.
+func UnmarshalLenient(data []byte) (*Objects, []Warning, error) {
+	return Decoder{Limits: DefaultLimits}.UnmarshalLenient(data)
+}
+
+// UnmarshalLenient decodes as much of a damaged document as it can and
+// reports everything it changed as warnings:
+//
+//   - unixTime and dateTime attributes that cannot be parsed are dropped
+//   - values whose text does not match their type are skipped
+//   - unknown attributes are kept but reported
+//   - elements left open by a mismatched end tag, a syntax error or a
+//     truncated document are closed, and a value cut short is skipped
+//
+// Limits are enforced as by Unmarshal and exceeding them is an error.
+func (d Decoder) UnmarshalLenient(data []byte) (*Objects, []Warning, error) {
+	if err := xmlscan.CheckSize(data, d.Limits.MaxBytes); err != nil {
+		return nil, nil, err
+	}
+
+	r := &repairer{decoder: xml.NewDecoder(bytes.NewReader(data)), maxDepth: d.Limits.MaxDepth}
+	r.decoder.Strict = false
+	r.decoder.Entity = xml.HTMLEntity
+	if err := r.repair(); err != nil {
+		return nil, r.warnings, err
+	}
+
+	limits := d.Limits
+	limits.MaxBytes = 0
+	objects, err := Decoder{Limits: limits}.Unmarshal(r.out.Bytes())
+	if err != nil {
+		return nil, r.warnings, err
+	}
+	return objects, r.warnings, nil
+}
+
+type openElement struct {
+	name    xml.Name
+	segment string
+}
+
+// repairer copies a document token by token into out, leaving out or
+// fixing the fragments that would make it fail to decode.
+type repairer struct {
+	decoder  *xml.Decoder
+	maxDepth int
+	out      bytes.Buffer
+	stack    []openElement
+	warnings []Warning
+
+	line, column int
+	text         strings.Builder
+
+	// value buffers the value element being copied until its text can be
+	// checked against valueType; valueDepth is its depth in stack.
+	value      *bytes.Buffer
+	valueDepth int
+	valueType  string
+}
+
+func (r *repairer) warn(attr string, err error) {
+	names := make([]string, len(r.stack))
+	path := Path{}
+	for i, e := range r.stack {
+		names[i] = e.name.Local
+		if e.segment != "" {
+			path = append(path, e.segment)
+		}
+	}
+	r.warnings = append(r.warnings, Warning{path, &DecodeError{
+		Line: r.line, Column: r.column, Path: strings.Join(names, "/"), Attr: attr, Err: err,
+	}})
+}
+
+func (r *repairer) writer() *bytes.Buffer {
+	if r.value != nil {
+		return r.value
+	}
+	return &r.out
+}
+
+// repair fails only when the document is nested deeper than maxDepth,
+// which also bounds the work of matching end elements.
+func (r *repairer) repair() error {
+	for {
+		r.line, r.column = r.decoder.InputPos()
+		token, err := r.decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			r.warn("", err)
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if r.maxDepth > 0 && len(r.stack) >= r.maxDepth {
+				return &DecodeError{Line: r.line, Column: r.column, Err: &LimitError{
+					Err: ErrTooDeep, Limit: int64(r.maxDepth), Offset: r.decoder.InputOffset(),
+				}}
+			}
+			r.start(t)
+		case xml.EndElement:
+			r.end(t)
+		case xml.CharData:
+			r.text.Write(t)
+			xml.EscapeText(r.writer(), t)
+		}
+	}
+	if len(r.stack) > 0 {
+		r.warn("", errors.New("document ends before its elements are closed"))
+	}
+	for len(r.stack) > 0 {
+		r.close(false)
+	}
+	return nil
+}
+
+func (r *repairer) start(t xml.StartElement) {
+	parent := ""
+	if len(r.stack) > 0 {
+		parent = r.stack[len(r.stack)-1].name.Local
+	}
+	r.stack = append(r.stack, openElement{name: t.Name})
+	r.text.Reset()
+
+	local := t.Name.Local
+	attrs := t.Attr[:0:0]
+	for _, attr := range t.Attr {
+		switch {
+		case attr.Name.Space == "xmlns" || attr.Name.Space == "xsi" || attr.Name.Space == "xml" || attr.Name.Local == "xmlns":
+		case local == "value" && attr.Name.Local == "unixTime":
+			if _, err := strconv.ParseInt(strings.TrimSpace(attr.Value), 10, 64); err != nil {
+				r.warn(attr.Name.Local, fmt.Errorf("dropped invalid unixTime %q", attr.Value))
+				continue
+			}
+		case local == "value" && attr.Name.Local == "dateTime":
+			if _, ok := parseDateTime(attr.Value); !ok {
+				r.warn(attr.Name.Local, fmt.Errorf("dropped invalid dateTime %q", attr.Value))
+				continue
+			}
+		case knownAttrs[local] != nil && !containsString(knownAttrs[local], attr.Name.Local):
+			r.warn(attr.Name.Local, errors.New("unknown attribute"))
+		}
+		attrs = append(attrs, attr)
+	}
+
+	switch {
+	case local == "InfoItem":
+		for _, attr := range t.Attr {
+			if attr.Name.Local == "name" {
+				r.stack[len(r.stack)-1].segment = attr.Value
+			}
+		}
+	case local == "value" && parent == "InfoItem" && r.value == nil:
+		r.value = &bytes.Buffer{}
+		r.valueDepth = len(r.stack)
+		r.valueType = ""
+		for _, attr := range attrs {
+			if attr.Name.Local == "type" {
+				r.valueType = attr.Value
+			}
+		}
+	}
+
+	w := r.writer()
+	w.WriteString("<" + qualifiedName(t.Name))
+	for _, attr := range attrs {
+		w.WriteString(" " + qualifiedName(attr.Name) + `="`)
+		xml.EscapeText(w, []byte(attr.Value))
+		w.WriteString(`"`)
+	}
+	w.WriteString(">")
+}
+
+func (r *repairer) end(t xml.EndElement) {
+	for i := len(r.stack) - 1; i >= 0; i-- {
+		if r.stack[i].name == t.Name {
+			for len(r.stack) > i+1 {
+				r.warn("", fmt.Errorf("element <%s> closed by </%s>", r.stack[len(r.stack)-1].name.Local, qualifiedName(t.Name)))
+				r.close(true)
+			}
+			r.close(true)
+			return
+		}
+	}
+	r.warn("", fmt.Errorf("skipped unexpected </%s>", qualifiedName(t.Name)))
+}
+
+// close ends the innermost open element. A value that is not complete or
+// whose text does not match its type is left out.
+func (r *repairer) close(complete bool) {
+	e := r.stack[len(r.stack)-1]
+	r.writer().WriteString("</" + qualifiedName(e.name) + ">")
+
+	if e.name.Local == "id" && len(r.stack) > 1 {
+		if parent := &r.stack[len(r.stack)-2]; parent.name.Local == "Object" && parent.segment == "" {
+			parent.segment = strings.TrimSpace(r.text.String())
+		}
+	}
+	if r.value != nil && len(r.stack) == r.valueDepth {
+		value := r.value
+		r.value = nil
+		err := CheckFormat(r.valueType, Value{Text: r.text.String()})
+		switch {
+		case !complete:
+			r.warn("", errors.New("skipped incomplete value"))
+		case err != nil:
+			r.warn("", fmt.Errorf("skipped value: %v", err))
+		default:
+			r.out.Write(value.Bytes())
+		}
+	}
+	r.stack = r.stack[:len(r.stack)-1]
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
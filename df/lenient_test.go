@@ -0,0 +1,139 @@
+package df
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func warningStrings(warnings []Warning) []string {
+	s := make([]string, len(warnings))
+	for i, w := range warnings {
+		s[i] = w.String()
+	}
+	return s
+}
+
+func TestUnmarshalLenientOfValidDocuments(t *testing.T) {
+	files, _ := filepath.Glob("examples/*.xml")
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if assert.Nil(t, err) {
+			strict, err := Unmarshal(data)
+			assert.Nil(t, err)
+			lenient, warnings, err := UnmarshalLenient(data)
+			if assert.Nil(t, err, file) {
+				assert.Empty(t, warnings, file)
+				assert.True(t, Equal(*strict, *lenient), file)
+			}
+		}
+	}
+}
+
+func TestUnmarshalLenient(t *testing.T) {
+	data := `<Objects>
+    <Object vendor="acme">
+        <id>SmartFridge22334411</id>
+        <InfoItem name="PowerConsumption">
+            <value type="xs:int" unixTime="yesterday">43</value>
+            <value type="xs:int" unixTime="5453563">4.5</value>
+            <value type="xs:int" dateTime="soon">44</value>
+            <value>no type &amp; fine</value>
+        </InfoItem>
+    </Object>
+</Objects>`
+	_, err := Unmarshal([]byte(data))
+	assert.NotNil(t, err)
+
+	objects, warnings, err := UnmarshalLenient([]byte(data))
+	if assert.Nil(t, err) {
+		item := objects.InfoItem(ParsePath("SmartFridge22334411/PowerConsumption"))
+		if assert.NotNil(t, item) {
+			assert.Equal(t, []Value{
+				Value{Type: "xs:int", Text: "43"},
+				Value{Type: "xs:int", Text: "44"},
+				Value{Text: "no type & fine"},
+			}, item.Values)
+		}
+	}
+	assert.Equal(t, []string{
+		`Objects: 2:5: Objects/Object@vendor: unknown attribute`,
+		`Objects/SmartFridge22334411/PowerConsumption: 5:13: Objects/Object/InfoItem/value@unixTime: dropped invalid unixTime "yesterday"`,
+		`Objects/SmartFridge22334411/PowerConsumption: 6:56: Objects/Object/InfoItem/value: skipped value: df: value does not match format: "4.5" is not xs:int`,
+		`Objects/SmartFridge22334411/PowerConsumption: 7:13: Objects/Object/InfoItem/value@dateTime: dropped invalid dateTime "soon"`,
+	}, warningStrings(warnings))
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(warnings[1].Err, &decodeErr))
+	assert.Equal(t, Path{"SmartFridge22334411", "PowerConsumption"}, warnings[1].Path)
+}
+
+func TestUnmarshalLenientOfBrokenStructure(t *testing.T) {
+	data := `<Objects>
+    <Object>
+        <id>Fridge</id>
+        <InfoItem name="Door"><value>open</value></Object>
+    <Object>
+        <id>Oven</id>
+        <InfoItem name="Light"><value>on</value></InfoItem></InfoItem>
+        <InfoItem name="Temperature">
+            <value type="xs:double">180</value>
+            <value type="xs:double">18`
+	objects, warnings, err := UnmarshalLenient([]byte(data))
+	if assert.Nil(t, err) {
+		assert.NotNil(t, objects.InfoItem(ParsePath("Fridge/Door")))
+		assert.NotNil(t, objects.InfoItem(ParsePath("Oven/Light")))
+		item := objects.InfoItem(ParsePath("Oven/Temperature"))
+		if assert.NotNil(t, item) {
+			assert.Equal(t, []Value{Value{Type: "xs:double", Text: "180"}}, item.Values)
+		}
+	}
+	assert.Equal(t, []string{
+		`Objects/Fridge/Door: 4:50: Objects/Object/InfoItem: element <InfoItem> closed by </Object>`,
+		`Objects/Oven: 7:60: Objects/Object: skipped unexpected </InfoItem>`,
+		`Objects/Oven/Temperature: 10:39: Objects/Object/InfoItem/value: document ends before its elements are closed`,
+		`Objects/Oven/Temperature: 10:39: Objects/Object/InfoItem/value: skipped incomplete value`,
+	}, warningStrings(warnings))
+}
+
+func TestUnmarshalLenientKeepsLimits(t *testing.T) {
+	data := []byte(`<Objects><Object><id>a</id></Object><Object><id>b</id></Object></Objects>`)
+	_, _, err := Decoder{Limits: Limits{MaxObjects: 1}}.UnmarshalLenient(data)
+	assert.True(t, errors.Is(err, ErrTooManyObjects))
+	_, _, err = Decoder{Limits: Limits{MaxBytes: 10}}.UnmarshalLenient(data)
+	assert.True(t, errors.Is(err, ErrTooLarge))
+
+	deep := strings.Repeat("<Object>", 1000) + strings.Repeat("</InfoItem>", 1000)
+	_, _, err = UnmarshalLenient([]byte("<Objects>" + deep))
+	assert.True(t, errors.Is(err, ErrTooDeep))
+
+	_, _, err = UnmarshalLenient([]byte("not xml"))
+	assert.NotNil(t, err)
+}
+
+func FuzzUnmarshalLenient(f *testing.F) {
+	files, _ := filepath.Glob("examples/*.xml")
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			f.Add(data)
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		objects, warnings, err := Decoder{Limits: Limits{MaxBytes: 4096, MaxDepth: 16}}.UnmarshalLenient(data)
+		if err != nil {
+			return
+		}
+		for _, w := range warnings {
+			var decodeErr *DecodeError
+			if !errors.As(w.Err, &decodeErr) {
+				t.Fatalf("warning without position: %v", w.Err)
+			}
+		}
+		if _, err := Marshal(*objects); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
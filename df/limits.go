@@ -0,0 +1,77 @@
+package df
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/qlm-iot/qlm/internal/xmlscan"
+)
+
+var (
+	ErrTooLarge       = xmlscan.ErrTooLarge
+	ErrTooDeep        = xmlscan.ErrTooDeep
+	ErrAttrTooLong    = xmlscan.ErrAttrTooLong
+	ErrTooManyObjects = errors.New("too many Objects")
+	ErrTooManyValues  = errors.New("too many values in an InfoItem")
+)
+
+// LimitError is returned for a document that exceeds its Limits. It wraps
+// one of the Err values above.
+type LimitError = xmlscan.LimitError
+
+// Limits bound the documents a Decoder accepts. Zero means no limit.
+type Limits struct {
+	MaxBytes   int64
+	MaxDepth   int
+	MaxObjects int
+	MaxValues  int
+	MaxAttrLen int
+}
+
+// DefaultLimits are used by Unmarshal.
+var DefaultLimits = Limits{
+	MaxBytes:   16 << 20,
+	MaxDepth:   128,
+	MaxObjects: 100000,
+	MaxValues:  100000,
+	MaxAttrLen: 64 << 10,
+}
+
+// scan checks data against l and checks the attributes that encoding/xml
+// would fail to decode, so that errors carry their position.
+func (l Limits) scan(data []byte) error {
+	objects := 0
+	values := []int{}
+	return xmlscan.Scan(data, xmlscan.Limits{
+		MaxBytes:   l.MaxBytes,
+		MaxDepth:   l.MaxDepth,
+		MaxAttrLen: l.MaxAttrLen,
+	}, func(s *xmlscan.Scanner, token xml.Token) error {
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "Object":
+				objects++
+				if l.MaxObjects > 0 && objects > l.MaxObjects {
+					return s.Exceeded(ErrTooManyObjects, l.MaxObjects)
+				}
+			case "InfoItem":
+				values = append(values, 0)
+			case "value":
+				if err := s.CheckInt(t, "unixTime", 64); err != nil {
+					return err
+				}
+				if len(values) > 0 && s.Stack[len(s.Stack)-2].Name.Local == "InfoItem" {
+					values[len(values)-1]++
+					if l.MaxValues > 0 && values[len(values)-1] > l.MaxValues {
+						return s.Exceeded(ErrTooManyValues, l.MaxValues)
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "InfoItem" {
+				values = values[:len(values)-1]
+			}
+		}
+		return nil
+	})
+}
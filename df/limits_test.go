@@ -0,0 +1,91 @@
+package df
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecoderLimits(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/object_with_sub_objects.xml")
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = Decoder{}.Unmarshal(data)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		limits Limits
+		err    error
+	}{
+		{Limits{MaxBytes: 100}, ErrTooLarge},
+		{Limits{MaxDepth: 3}, ErrTooDeep},
+		{Limits{MaxObjects: 1}, ErrTooManyObjects},
+		{Limits{MaxAttrLen: 10}, ErrAttrTooLong},
+	} {
+		_, err := Decoder{Limits: c.limits}.Unmarshal(data)
+		assert.True(t, errors.Is(err, c.err), "%v: %v", c.limits, err)
+		var limit *LimitError
+		assert.True(t, errors.As(err, &limit))
+	}
+}
+
+func TestDecoderMaxValues(t *testing.T) {
+	doc := `<Objects><Object><id>a</id><InfoItem name="b">` +
+		`<MetaData><InfoItem name="unit"><value>W</value></InfoItem></MetaData>` +
+		`<value>1</value><value>2</value></InfoItem></Object></Objects>`
+	_, err := Decoder{Limits: Limits{MaxValues: 2}}.Unmarshal([]byte(doc))
+	assert.Nil(t, err)
+	_, err = Decoder{Limits: Limits{MaxValues: 1}}.Unmarshal([]byte(doc))
+	assert.True(t, errors.Is(err, ErrTooManyValues))
+}
+
+func TestUnmarshalRejectsHostileDocuments(t *testing.T) {
+	deep := strings.Repeat("<Object>", 1000) + strings.Repeat("</Object>", 1000)
+	_, err := Unmarshal([]byte("<Objects>" + deep + "</Objects>"))
+	assert.True(t, errors.Is(err, ErrTooDeep))
+
+	values := strings.Repeat("<value>1</value>", DefaultLimits.MaxValues+1)
+	_, err = Unmarshal([]byte(`<Objects><Object><id>a</id><InfoItem name="b">` + values + `</InfoItem></Object></Objects>`))
+	assert.True(t, errors.Is(err, ErrTooManyValues))
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	files, _ := filepath.Glob("examples/*.xml")
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			f.Add(data)
+		}
+	}
+	limits := Limits{MaxBytes: 4096, MaxDepth: 16, MaxObjects: 8, MaxValues: 8, MaxAttrLen: 64}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		objects, err := Decoder{Limits: limits}.Unmarshal(data)
+		if err != nil {
+			return
+		}
+		count := 0
+		var walk func(objects []Object, depth int)
+		walk = func(objects []Object, depth int) {
+			for _, object := range objects {
+				count++
+				for _, item := range object.InfoItems {
+					if len(item.Values) > limits.MaxValues {
+						t.Fatalf("%d values in %s", len(item.Values), item.Name)
+					}
+				}
+				walk(object.Objects, depth+1)
+			}
+		}
+		walk(objects.Objects, 1)
+		if count > limits.MaxObjects {
+			t.Fatalf("%d Objects", count)
+		}
+		if _, err := Marshal(*objects); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package df
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrFormat = errors.New("df: value does not match format")
+
+var metaNames = map[string]bool{
+	"format": true, "latency": true, "readable": true,
+	"writable": true, "unit": true, "accuracy": true,
+}
+
+var intSizes = map[string]int{
+	"xs:integer": 64, "xs:long": 64, "xs:int": 32, "xs:short": 16, "xs:byte": 8,
+	"xs:nonNegativeInteger": 64, "xs:unsignedLong": 64, "xs:unsignedInt": 32,
+	"xs:unsignedShort": 16, "xs:unsignedByte": 8,
+}
+
+// Meta is a typed view of the well-known MetaData InfoItems. Unset fields
+// are left out of the MetaData; any other InfoItems are kept in Extra.
+type Meta struct {
+	Format   string
+	Latency  *int
+	Readable *bool
+	Writable *bool
+	Unit     string
+	Accuracy *float64
+	Extra    []InfoItem
+}
+
+// ParseMeta reads the latest value of every well-known MetaData InfoItem.
+// A nil MetaData gives an empty Meta.
+func ParseMeta(m *MetaData) (Meta, error) {
+	meta := Meta{}
+	if m == nil {
+		return meta, nil
+	}
+	for _, item := range m.InfoItems {
+		if !metaNames[item.Name] {
+			meta.Extra = append(meta.Extra, item)
+			continue
+		}
+		if len(item.Values) == 0 {
+			continue
+		}
+		text := strings.TrimSpace(item.Values[len(item.Values)-1].Text)
+		var err error
+		switch item.Name {
+		case "format":
+			meta.Format = text
+		case "latency":
+			var latency int
+			latency, err = strconv.Atoi(text)
+			meta.Latency = &latency
+		case "readable":
+			var readable bool
+			readable, err = strconv.ParseBool(text)
+			meta.Readable = &readable
+		case "writable":
+			var writable bool
+			writable, err = strconv.ParseBool(text)
+			meta.Writable = &writable
+		case "unit":
+			meta.Unit = text
+		case "accuracy":
+			var accuracy float64
+			accuracy, err = strconv.ParseFloat(text, 64)
+			meta.Accuracy = &accuracy
+		}
+		if err != nil {
+			return Meta{}, fmt.Errorf("df: MetaData %s: invalid value %q", item.Name, text)
+		}
+	}
+	return meta, nil
+}
+
+// Meta parses the MetaData of the InfoItem.
+func (i *InfoItem) Meta() (Meta, error) {
+	return ParseMeta(i.MetaData)
+}
+
+// MetaData converts the view back to MetaData InfoItems, or nil if it is
+// empty.
+func (m Meta) MetaData() *MetaData {
+	items := []InfoItem{}
+	add := func(name, valueType, text string) {
+		items = append(items, InfoItem{Name: name, Values: []Value{Value{Type: valueType, Text: text}}})
+	}
+	if m.Format != "" {
+		add("format", "xs:string", m.Format)
+	}
+	if m.Latency != nil {
+		add("latency", "xs:int", strconv.Itoa(*m.Latency))
+	}
+	if m.Readable != nil {
+		add("readable", "xs:boolean", strconv.FormatBool(*m.Readable))
+	}
+	if m.Writable != nil {
+		add("writable", "xs:boolean", strconv.FormatBool(*m.Writable))
+	}
+	if m.Unit != "" {
+		add("unit", "xs:string", m.Unit)
+	}
+	if m.Accuracy != nil {
+		add("accuracy", "xs:double", strconv.FormatFloat(*m.Accuracy, 'g', -1, 64))
+	}
+	items = append(items, m.Extra...)
+	if len(items) == 0 {
+		return nil
+	}
+	return &MetaData{InfoItems: items}
+}
+
+// Extension returns the latest value of the extra MetaData InfoItem name.
+func (m Meta) Extension(name string) (Value, bool) {
+	for _, item := range m.Extra {
+		if item.Name == name && len(item.Values) > 0 {
+			return item.Values[len(item.Values)-1], true
+		}
+	}
+	return Value{}, false
+}
+
+// CanRead and CanWrite default to true when the MetaData says nothing.
+func (m Meta) CanRead() bool {
+	return m.Readable == nil || *m.Readable
+}
+
+func (m Meta) CanWrite() bool {
+	return m.Writable == nil || *m.Writable
+}
+
+// Check reports whether v matches the declared format.
+func (m Meta) Check(v Value) error {
+	return CheckFormat(m.Format, v)
+}
+
+// CheckFormat returns an error wrapping ErrFormat when the text of v is not
+// a valid lexical value of the XML Schema type format. Unknown and empty
+// formats accept any value.
+func CheckFormat(format string, v Value) error {
+	text := strings.TrimSpace(v.Text)
+	format = strings.TrimSpace(format)
+	var err error
+	switch {
+	case intSizes[format] != 0 && (strings.HasPrefix(format, "xs:unsigned") || format == "xs:nonNegativeInteger"):
+		_, err = strconv.ParseUint(text, 10, intSizes[format])
+	case intSizes[format] != 0:
+		_, err = strconv.ParseInt(text, 10, intSizes[format])
+	case format == "xs:double" || format == "xs:float" || format == "xs:decimal":
+		_, err = strconv.ParseFloat(text, 64)
+	case format == "xs:boolean":
+		if text != "true" && text != "false" && text != "1" && text != "0" {
+			err = ErrFormat
+		}
+	case format == "xs:dateTime":
+		if _, ok := parseDateTime(text); !ok {
+			err = ErrFormat
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %q is not %s", ErrFormat, text, format)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package df
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestParseMeta(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/metadata_about_refrigerator_power_consumption.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			item := v.InfoItem(ParsePath("SmartFridge22334411/PowerConsumption"))
+			meta, err := item.Meta()
+			if assert.Nil(t, err) {
+				assert.Equal(t, "xs:double", meta.Format)
+				assert.Equal(t, 5, *meta.Latency)
+				assert.True(t, meta.CanRead())
+				assert.False(t, meta.CanWrite())
+				assert.Equal(t, "Watts", meta.Unit)
+				assert.Equal(t, 1.0, *meta.Accuracy)
+				assert.Empty(t, meta.Extra)
+				assert.Equal(t, item.MetaData, meta.MetaData())
+			}
+		}
+	}
+}
+
+func TestParseMetaWithExtraItems(t *testing.T) {
+	m := &MetaData{InfoItems: []InfoItem{
+		InfoItem{Name: "unit", Values: []Value{Value{Text: " °C "}}},
+		InfoItem{Name: "vendor", Values: []Value{Value{Text: "ACME"}}},
+		InfoItem{Name: "writable"},
+	}}
+	meta, err := ParseMeta(m)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "°C", meta.Unit)
+		assert.Nil(t, meta.Writable)
+		assert.True(t, meta.CanWrite())
+		vendor, ok := meta.Extension("vendor")
+		assert.True(t, ok)
+		assert.Equal(t, "ACME", vendor.Text)
+		_, ok = meta.Extension("unit")
+		assert.False(t, ok)
+		assert.Len(t, meta.MetaData().InfoItems, 2)
+	}
+
+	meta, err = ParseMeta(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, meta.MetaData())
+
+	_, err = ParseMeta(&MetaData{InfoItems: []InfoItem{InfoItem{Name: "latency", Values: []Value{Value{Text: "soon"}}}}})
+	assert.EqualError(t, err, `df: MetaData latency: invalid value "soon"`)
+}
+
+func TestCheckFormat(t *testing.T) {
+	valid := map[string][]string{
+		"xs:double":       {"1", "-2.5e3", " 43 "},
+		"xs:int":          {"42", "-7"},
+		"xs:unsignedByte": {"255"},
+		"xs:boolean":      {"true", "0"},
+		"xs:dateTime":     {"2015-03-04T12:00:00Z"},
+		"xs:string":       {"anything"},
+		"":                {"anything"},
+	}
+	for format, texts := range valid {
+		for _, text := range texts {
+			assert.Nil(t, CheckFormat(format, Value{Text: text}), "%s %s", format, text)
+		}
+	}
+	invalid := map[string][]string{
+		"xs:double":       {"warm", ""},
+		"xs:int":          {"1.5", "3000000000"},
+		"xs:unsignedByte": {"-1", "256"},
+		"xs:boolean":      {"yes"},
+		"xs:dateTime":     {"yesterday"},
+	}
+	for format, texts := range invalid {
+		for _, text := range texts {
+			err := CheckFormat(format, Value{Text: text})
+			assert.True(t, errors.Is(err, ErrFormat), "%s %s", format, text)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package df
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Normalize returns the canonical form of a tree: text is trimmed, Objects
+// are sorted by id, InfoItems by name, values by time and other
+// descriptions by language and text, and Objects or InfoItems that appear
+// more than once under the same parent are merged.
+func Normalize(objects Objects) Objects {
+	objects.Objects = normalizeObjects(objects.Objects)
+	return objects
+}
+
+// Equal reports whether two trees have the same canonical form. The schema
+// attributes of the Objects element are not compared.
+func Equal(a, b Objects) bool {
+	a, b = Normalize(a), Normalize(b)
+	a.XmlnsXsi, a.NoNamespaceSchemaLocation = "", ""
+	b.XmlnsXsi, b.NoNamespaceSchemaLocation = "", ""
+	return reflect.DeepEqual(a, b)
+}
+
+func normalizeObjects(objects []Object) []Object {
+	normalized := []Object{}
+	index := map[string]int{}
+	for _, o := range objects {
+		o.Id = normalizeId(o.Id)
+		o.OtherIds = normalizeOtherIds(o.OtherIds)
+		o.Description = normalizeDescription(o.Description)
+		o.OtherDescriptions = normalizeOtherDescriptions(o.OtherDescriptions)
+		id := o.ID()
+		if i, ok := index[id]; ok && id != "" {
+			merged := &normalized[i]
+			merged.Type = firstNonEmpty(merged.Type, o.Type)
+			merged.Udef = firstNonEmpty(merged.Udef, o.Udef)
+			for _, other := range o.Ids()[1:] {
+				if !merged.HasId(other.Text, nil) {
+					merged.AddId(other)
+				}
+			}
+			for _, description := range o.Descriptions() {
+				if !containsDescription(merged.Descriptions(), description) {
+					merged.AddDescription(description)
+				}
+			}
+			merged.InfoItems = append(merged.InfoItems, o.InfoItems...)
+			merged.Objects = append(merged.Objects, o.Objects...)
+			continue
+		}
+		o.InfoItems = append([]InfoItem(nil), o.InfoItems...)
+		o.Objects = append([]Object(nil), o.Objects...)
+		index[id] = len(normalized)
+		normalized = append(normalized, o)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	for i := range normalized {
+		sortDescriptions(normalized[i].OtherDescriptions)
+		normalized[i].InfoItems = normalizeInfoItems(normalized[i].InfoItems)
+		normalized[i].Objects = normalizeObjects(normalized[i].Objects)
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		if normalized[i].ID() != normalized[j].ID() {
+			return normalized[i].ID() < normalized[j].ID()
+		}
+		return normalized[i].Type < normalized[j].Type
+	})
+	return normalized
+}
+
+func normalizeInfoItems(items []InfoItem) []InfoItem {
+	normalized := []InfoItem{}
+	index := map[string]int{}
+	for _, item := range items {
+		item.Name = strings.TrimSpace(item.Name)
+		item.Description = normalizeDescription(item.Description)
+		if i, ok := index[item.Name]; ok {
+			merged := &normalized[i]
+			merged.Udef = firstNonEmpty(merged.Udef, item.Udef)
+			if merged.Description == nil {
+				merged.Description = item.Description
+			}
+			if merged.MetaData == nil {
+				merged.MetaData = item.MetaData
+			}
+			merged.OtherNames = append(merged.OtherNames, item.OtherNames...)
+			merged.Values = append(merged.Values, item.Values...)
+			continue
+		}
+		item.OtherNames = append([]string(nil), item.OtherNames...)
+		item.Values = append([]Value(nil), item.Values...)
+		index[item.Name] = len(normalized)
+		normalized = append(normalized, item)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	for i := range normalized {
+		item := &normalized[i]
+		item.OtherNames = normalizeNames(item.OtherNames)
+		item.Values = normalizeValues(item.Values)
+		if item.MetaData != nil {
+			item.MetaData = &MetaData{InfoItems: normalizeInfoItems(item.MetaData.InfoItems)}
+		}
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return normalized[i].Name < normalized[j].Name
+	})
+	return normalized
+}
+
+func normalizeValues(values []Value) []Value {
+	if len(values) == 0 {
+		return nil
+	}
+	for i := range values {
+		values[i].Text = strings.TrimSpace(values[i].Text)
+		values[i].Type = strings.TrimSpace(values[i].Type)
+		values[i].DateTime = strings.TrimSpace(values[i].DateTime)
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		ti, _ := values[i].Time()
+		tj, _ := values[j].Time()
+		return ti.Before(tj)
+	})
+	normalized := values[:1]
+	for _, v := range values[1:] {
+		if v != normalized[len(normalized)-1] {
+			normalized = append(normalized, v)
+		}
+	}
+	return normalized
+}
+
+func normalizeNames(names []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func normalizeId(id *QLMID) *QLMID {
+	if id == nil {
+		return nil
+	}
+	normalized := *id
+	normalized.Text = strings.TrimSpace(normalized.Text)
+	return &normalized
+}
+
+func normalizeOtherIds(ids []QLMID) []QLMID {
+	if len(ids) == 0 {
+		return nil
+	}
+	normalized := make([]QLMID, len(ids))
+	for i := range ids {
+		normalized[i] = *normalizeId(&ids[i])
+	}
+	return normalized
+}
+
+func normalizeOtherDescriptions(descriptions []Description) []Description {
+	if len(descriptions) == 0 {
+		return nil
+	}
+	normalized := make([]Description, len(descriptions))
+	for i := range descriptions {
+		normalized[i] = *normalizeDescription(&descriptions[i])
+	}
+	return normalized
+}
+
+func sortDescriptions(descriptions []Description) {
+	sort.SliceStable(descriptions, func(i, j int) bool {
+		if descriptions[i].Lang != descriptions[j].Lang {
+			return descriptions[i].Lang < descriptions[j].Lang
+		}
+		return descriptions[i].Text < descriptions[j].Text
+	})
+}
+
+func containsDescription(descriptions []Description, description Description) bool {
+	for _, d := range descriptions {
+		if d == description {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDescription(description *Description) *Description {
+	if description == nil {
+		return nil
+	}
+	normalized := *description
+	normalized.Text = strings.TrimSpace(normalized.Text)
+	return &normalized
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,164 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeSortsAndTrims(t *testing.T) {
+	objects := Objects{
+		Objects: []Object{
+			Object{
+				Id: &QLMID{Text: " Fridge2 "},
+			},
+			Object{
+				Id: &QLMID{Text: "Fridge1"},
+				InfoItems: []InfoItem{
+					InfoItem{Name: "Temperature", Values: []Value{
+						Value{Text: " 4.5 ", DateTime: "2014-01-01T00:10"},
+						Value{Text: "4.0", UnixTime: 1388534400},
+					}},
+					InfoItem{Name: "Power", Description: &Description{Text: "\n\t Power consumption \n"}},
+				},
+			},
+		},
+	}
+
+	normalized := Normalize(objects)
+	if assert.Len(t, normalized.Objects, 2) {
+		assert.Equal(t, "Fridge1", normalized.Objects[0].Id.Text)
+		assert.Equal(t, "Fridge2", normalized.Objects[1].Id.Text)
+		items := normalized.Objects[0].InfoItems
+		if assert.Len(t, items, 2) {
+			assert.Equal(t, "Power", items[0].Name)
+			assert.Equal(t, "Power consumption", items[0].Description.Text)
+			assert.Equal(t, "Temperature", items[1].Name)
+			assert.Equal(t, []Value{
+				Value{Text: "4.0", UnixTime: 1388534400},
+				Value{Text: "4.5", DateTime: "2014-01-01T00:10"},
+			}, items[1].Values)
+		}
+	}
+	assert.Equal(t, " Fridge2 ", objects.Objects[0].Id.Text)
+	assert.Equal(t, " 4.5 ", objects.Objects[1].InfoItems[0].Values[0].Text)
+}
+
+func TestNormalizeCollapsesDuplicates(t *testing.T) {
+	objects := Objects{
+		Objects: []Object{
+			Object{
+				Id: &QLMID{Text: "Fridge"},
+				InfoItems: []InfoItem{
+					InfoItem{Name: "Power", Values: []Value{Value{Text: "1", UnixTime: 1}}},
+					InfoItem{Name: "Power", Udef: "b.o.9_1.1.14.13", Values: []Value{Value{Text: "2", UnixTime: 2}, Value{Text: "1", UnixTime: 1}}},
+				},
+			},
+			Object{
+				Id:        &QLMID{Text: "Fridge"},
+				Type:      "Refrigerator",
+				InfoItems: []InfoItem{InfoItem{Name: "Door"}},
+			},
+		},
+	}
+
+	normalized := Normalize(objects)
+	if assert.Len(t, normalized.Objects, 1) {
+		assert.Equal(t, "Refrigerator", normalized.Objects[0].Type)
+		items := normalized.Objects[0].InfoItems
+		if assert.Len(t, items, 2) {
+			assert.Equal(t, "Door", items[0].Name)
+			assert.Equal(t, "b.o.9_1.1.14.13", items[1].Udef)
+			assert.Equal(t, []Value{Value{Text: "1", UnixTime: 1}, Value{Text: "2", UnixTime: 2}}, items[1].Values)
+		}
+	}
+}
+
+func TestNormalizeIsIdempotent(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+	once := Normalize(*v)
+	assert.Equal(t, once, Normalize(once))
+}
+
+func TestEqual(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+	reordered := Objects{
+		Objects: []Object{
+			Object{
+				Type: "someType",
+				Id:   &QLMID{Text: "UniqueTargetID_1"},
+				Objects: []Object{
+					Object{
+						Type: "someType",
+						Id:   &QLMID{Text: "SubTarget2"},
+						InfoItems: []InfoItem{
+							InfoItem{Name: "SubTarget2InfoItem1", Values: []Value{Value{Text: "34.6"}}},
+						},
+					},
+					Object{
+						Type:      "someType",
+						Id:        &QLMID{Text: "SubTarget1"},
+						InfoItems: []InfoItem{InfoItem{Name: "SubInfoItem1"}},
+						Objects: []Object{
+							Object{
+								Type: "someType",
+								Id:   &QLMID{Text: "SubSubTarget1"},
+								InfoItems: []InfoItem{
+									InfoItem{Name: "SubSubTarget1InfoItem1", Values: []Value{Value{Text: "22.5"}}},
+								},
+							},
+						},
+					},
+				},
+				InfoItems: []InfoItem{
+					InfoItem{Name: "InfoItem2", Values: []Value{Value{Text: "Value"}}},
+					InfoItem{Name: "InfoItem1", Values: []Value{Value{Text: "Value1"}, Value{Text: "Value2"}, Value{Text: "Value3"}}},
+				},
+			},
+		},
+	}
+	assert.True(t, Equal(*v, reordered))
+
+	reordered.Objects[0].InfoItems[0].Values[0].Text = "Other"
+	assert.False(t, Equal(*v, reordered))
+}
+
+func TestEqualIgnoresDescriptionOrder(t *testing.T) {
+	a := Objects{Objects: []Object{Object{
+		Id:          &QLMID{Text: "SmartFridge"},
+		Description: &Description{Lang: "en", Text: "Fridge"},
+		OtherDescriptions: []Description{
+			Description{Lang: "fi", Text: "Jääkaappi"},
+			Description{Lang: "de", Text: "Kühlschrank"},
+			Description{Lang: "de", Text: "Eisschrank"},
+		},
+	}}}
+	b := Objects{Objects: []Object{Object{
+		Id:          &QLMID{Text: "SmartFridge"},
+		Description: &Description{Lang: "en", Text: "Fridge"},
+		OtherDescriptions: []Description{
+			Description{Lang: "de", Text: "Kühlschrank"},
+			Description{Lang: "fi", Text: " Jääkaappi "},
+			Description{Lang: "de", Text: "Eisschrank"},
+		},
+	}}}
+	assert.True(t, Equal(a, b))
+	assert.Equal(t, Sum(a), Sum(b))
+	assert.Equal(t, []Description{
+		Description{Lang: "de", Text: "Eisschrank"},
+		Description{Lang: "de", Text: "Kühlschrank"},
+		Description{Lang: "fi", Text: "Jääkaappi"},
+	}, Normalize(b).Objects[0].OtherDescriptions)
+}
+
+func TestValueTime(t *testing.T) {
+	v, ok := Value{DateTime: "2001-10-26T15:33:21"}.Time()
+	if assert.True(t, ok) {
+		assert.Equal(t, int64(1004110401), v.Unix())
+	}
+	v, ok = Value{UnixTime: 1004110401}.Time()
+	if assert.True(t, ok) {
+		assert.Equal(t, int64(1004110401), v.Unix())
+	}
+	_, ok = Value{DateTime: "yesterday"}.Time()
+	assert.False(t, ok)
+}
@@ -0,0 +1,112 @@
+package df
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// objectXML is the XML form of an Object, in which all ids and all
+// descriptions are repeated elements.
+type objectXML struct {
+	Type         string        `xml:"type,attr,omitempty"`
+	Udef         string        `xml:"udef,attr,omitempty"`
+	Ids          []QLMID       `xml:"id"`
+	Descriptions []Description `xml:"description"`
+	InfoItems    []InfoItem    `xml:"InfoItem"`
+	Objects      []Object      `xml:"Object"`
+}
+
+func (o Object) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(objectXML{
+		Type:         o.Type,
+		Udef:         o.Udef,
+		Ids:          o.Ids(),
+		Descriptions: o.Descriptions(),
+		InfoItems:    o.InfoItems,
+		Objects:      o.Objects,
+	}, start)
+}
+
+func (o *Object) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	v := objectXML{}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*o = Object{Type: v.Type, Udef: v.Udef, InfoItems: v.InfoItems, Objects: v.Objects}
+	for _, id := range v.Ids {
+		o.AddId(id)
+	}
+	for _, description := range v.Descriptions {
+		o.AddDescription(description)
+	}
+	return nil
+}
+
+// AddId sets the Object's id, or adds another one if it already has one.
+func (o *Object) AddId(id QLMID) {
+	if o.Id == nil {
+		o.Id = &id
+		return
+	}
+	o.OtherIds = append(o.OtherIds, id)
+}
+
+// AddDescription sets the Object's description, or adds another one if it
+// already has one.
+func (o *Object) AddDescription(description Description) {
+	if o.Description == nil {
+		o.Description = &description
+		return
+	}
+	o.OtherDescriptions = append(o.OtherDescriptions, description)
+}
+
+// Descriptions returns every description of the Object.
+func (o *Object) Descriptions() []Description {
+	if o.Description == nil {
+		return nil
+	}
+	return append([]Description{*o.Description}, o.OtherDescriptions...)
+}
+
+// DescriptionFor returns the Object's description in the first of langs it
+// has, or nil if it has none at all; see SelectDescription.
+func (o *Object) DescriptionFor(langs ...string) *Description {
+	return SelectDescription(o.Descriptions(), langs...)
+}
+
+// SelectDescription picks the description best matching the language
+// preferences in langs, most preferred first. A language matches exactly or
+// by its primary subtag, so "en" matches "en-GB" and the other way round.
+// Without a match the description without a lang attribute is returned,
+// then the first one.
+func SelectDescription(descriptions []Description, langs ...string) *Description {
+	if len(descriptions) == 0 {
+		return nil
+	}
+	for _, lang := range langs {
+		for i := range descriptions {
+			if strings.EqualFold(descriptions[i].Lang, lang) {
+				return &descriptions[i]
+			}
+		}
+		for i := range descriptions {
+			if descriptions[i].Lang != "" && strings.EqualFold(primaryLang(descriptions[i].Lang), primaryLang(lang)) {
+				return &descriptions[i]
+			}
+		}
+	}
+	for i := range descriptions {
+		if descriptions[i].Lang == "" {
+			return &descriptions[i]
+		}
+	}
+	return &descriptions[0]
+}
+
+func primaryLang(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		return lang[:i]
+	}
+	return lang
+}
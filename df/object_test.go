@@ -0,0 +1,88 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const multipleIdsAndDescriptions = `<Objects xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="odf.xsd">
+    <Object type="Refrigerator">
+        <id idType="serial">SmartFridge22334411</id>
+        <id idType="epc" startDate="2014-01-01T00:00:00">urn:epc:id:sgtin:0614141.107346.2017</id>
+        <description lang="en">Fridge in the kitchen</description>
+        <description lang="fi">Keittiön jääkaappi</description>
+        <description>Kitchen fridge</description>
+        <InfoItem name="PowerConsumption"></InfoItem>
+    </Object>
+</Objects>`
+
+func TestUnmarshalObjectWithMultipleIdsAndDescriptions(t *testing.T) {
+	v, err := Unmarshal([]byte(multipleIdsAndDescriptions))
+	if assert.Nil(t, err) && assert.Len(t, v.Objects, 1) {
+		o := v.Objects[0]
+		assert.Equal(t, "SmartFridge22334411", o.Id.Text)
+		assert.Equal(t, "Fridge in the kitchen", o.Description.Text)
+		if assert.Len(t, o.OtherIds, 1) {
+			assert.Equal(t, "epc", o.OtherIds[0].IdType)
+		}
+		assert.Len(t, o.OtherDescriptions, 2)
+		assert.Len(t, o.Ids(), 2)
+		assert.Len(t, o.Descriptions(), 3)
+		assert.Len(t, o.InfoItems, 1)
+	}
+}
+
+func TestMarshalObjectWithMultipleIdsAndDescriptions(t *testing.T) {
+	v, err := Unmarshal([]byte(multipleIdsAndDescriptions))
+	if assert.Nil(t, err) {
+		AssertXML(t, *v, multipleIdsAndDescriptions)
+	}
+}
+
+func TestLookupByOtherId(t *testing.T) {
+	v, err := Unmarshal([]byte(multipleIdsAndDescriptions))
+	if assert.Nil(t, err) {
+		epc := ParsePath("urn:epc:id:sgtin:0614141.107346.2017/PowerConsumption")
+		assert.NotNil(t, v.InfoItem(epc))
+		assert.NotNil(t, v.InfoItemAt(epc, date("2014-06-01T00:00")))
+		assert.Nil(t, v.InfoItemAt(epc, date("2013-06-01T00:00")))
+
+		id := v.Objects[0].IdAt(time.Date(2014, 6, 1, 0, 0, 0, 0, time.UTC), "epc")
+		if assert.NotNil(t, id) {
+			assert.Equal(t, "urn:epc:id:sgtin:0614141.107346.2017", id.Text)
+		}
+	}
+}
+
+func TestDescriptionFor(t *testing.T) {
+	v, err := Unmarshal([]byte(multipleIdsAndDescriptions))
+	if assert.Nil(t, err) {
+		o := v.Objects[0]
+		assert.Equal(t, "Keittiön jääkaappi", o.DescriptionFor("fi").Text)
+		assert.Equal(t, "Keittiön jääkaappi", o.DescriptionFor("sv", "fi-FI", "en").Text)
+		assert.Equal(t, "Fridge in the kitchen", o.DescriptionFor("EN-us").Text)
+		assert.Equal(t, "Kitchen fridge", o.DescriptionFor("de").Text)
+		assert.Equal(t, "Kitchen fridge", o.DescriptionFor().Text)
+	}
+	assert.Nil(t, (&Object{}).DescriptionFor("en"))
+	only := Object{Description: &Description{Lang: "fi", Text: "Uuni"}}
+	assert.Equal(t, "Uuni", only.DescriptionFor("en").Text)
+}
+
+func TestNormalizeMergesIdsAndDescriptions(t *testing.T) {
+	objects := Objects{Objects: []Object{
+		Object{Id: &QLMID{Text: "Fridge"}, Description: &Description{Lang: "en", Text: "Fridge"}},
+		Object{
+			Id:                &QLMID{Text: "Fridge"},
+			OtherIds:          []QLMID{QLMID{IdType: "epc", Text: " EPC1 "}},
+			Description:       &Description{Lang: "en", Text: "Fridge"},
+			OtherDescriptions: []Description{Description{Lang: "fi", Text: "Jääkaappi"}},
+		},
+	}}
+	normalized := Normalize(objects)
+	if assert.Len(t, normalized.Objects, 1) {
+		assert.Equal(t, []QLMID{QLMID{IdType: "epc", Text: "EPC1"}}, normalized.Objects[0].OtherIds)
+		assert.Equal(t, []Description{Description{Lang: "fi", Text: "Jääkaappi"}}, normalized.Objects[0].OtherDescriptions)
+	}
+}
@@ -0,0 +1,152 @@
+package df
+
+import (
+	"strings"
+	"time"
+)
+
+// Path addresses a node in an O-DF tree by the ids of the enclosing Objects,
+// optionally followed by an InfoItem name, e.g. "Objects/SmartFridge/PowerConsumption".
+type Path []string
+
+func ParsePath(s string) Path {
+	s = strings.Trim(s, "/")
+	if s == "" || s == "Objects" {
+		return Path{}
+	}
+	s = strings.TrimPrefix(s, "Objects/")
+	return Path(strings.Split(s, "/"))
+}
+
+func (p Path) String() string {
+	return strings.Join(append([]string{"Objects"}, p...), "/")
+}
+
+func (p Path) HasPrefix(prefix Path) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Path) Child(name string) Path {
+	child := make(Path, len(p), len(p)+1)
+	copy(child, p)
+	return append(child, name)
+}
+
+// ID returns the text of the Object's id, or "" when it has none.
+func (o *Object) ID() string {
+	if o.Id == nil {
+		return ""
+	}
+	return strings.TrimSpace(o.Id.Text)
+}
+
+// Object returns the Object at path, treating every element as an Object id.
+// An Object matches an element by any of its ids, including expired ones;
+// ObjectAt matches only the ids that are valid at a given time.
+func (o *Objects) Object(path Path) *Object {
+	return o.lookup(path, nil)
+}
+
+func (o *Objects) lookup(path Path, at *time.Time) *Object {
+	if len(path) == 0 {
+		return nil
+	}
+	objects := o.Objects
+	var found *Object
+	for _, id := range path {
+		found = findChild(objects, id, at)
+		if found == nil {
+			return nil
+		}
+		objects = found.Objects
+	}
+	return found
+}
+
+func findChild(objects []Object, id string, at *time.Time) *Object {
+	for i := range objects {
+		if objects[i].HasId(id, at) {
+			return &objects[i]
+		}
+	}
+	return nil
+}
+
+// InfoItem returns the InfoItem at path, whose last element is the InfoItem name.
+func (o *Objects) InfoItem(path Path) *InfoItem {
+	if len(path) < 2 {
+		return nil
+	}
+	parent := o.Object(path[:len(path)-1])
+	if parent == nil {
+		return nil
+	}
+	return parent.InfoItem(path[len(path)-1])
+}
+
+func (o *Object) InfoItem(name string) *InfoItem {
+	for i := range o.InfoItems {
+		if o.InfoItems[i].Name == name {
+			return &o.InfoItems[i]
+		}
+	}
+	return nil
+}
+
+// AddObject returns the Object at path, creating it and any missing parent
+// Objects.
+func (o *Objects) AddObject(path Path) *Object {
+	objects := &o.Objects
+	var object *Object
+	for _, id := range path {
+		object = findChild(*objects, id, nil)
+		if object == nil {
+			*objects = append(*objects, Object{Id: &QLMID{Text: id}})
+			object = &(*objects)[len(*objects)-1]
+		}
+		objects = &object.Objects
+	}
+	return object
+}
+
+// Add appends values to the InfoItem at path, creating it and any missing
+// Objects on the way.
+func (o *Objects) Add(path Path, values ...Value) *InfoItem {
+	if len(path) < 2 {
+		return nil
+	}
+	parent := o.AddObject(path[:len(path)-1])
+	name := path[len(path)-1]
+	item := parent.InfoItem(name)
+	if item == nil {
+		parent.InfoItems = append(parent.InfoItems, InfoItem{Name: name})
+		item = &parent.InfoItems[len(parent.InfoItems)-1]
+	}
+	item.Values = append(item.Values, values...)
+	return item
+}
+
+// Walk calls fn for every InfoItem in the tree, depth first in document order.
+func (o *Objects) Walk(fn func(path Path, item *InfoItem)) {
+	for i := range o.Objects {
+		o.Objects[i].walk(Path{}, fn)
+	}
+}
+
+func (o *Object) walk(parent Path, fn func(path Path, item *InfoItem)) {
+	path := parent.Child(o.ID())
+	for i := range o.InfoItems {
+		fn(path.Child(o.InfoItems[i].Name), &o.InfoItems[i])
+	}
+	for i := range o.Objects {
+		o.Objects[i].walk(path, fn)
+	}
+}
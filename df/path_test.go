@@ -0,0 +1,84 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	assert.Equal(t, Path{"SmartFridge22334411", "PowerConsumption"}, ParsePath("Objects/SmartFridge22334411/PowerConsumption"))
+	assert.Equal(t, Path{"SmartFridge22334411"}, ParsePath("/SmartFridge22334411/"))
+	assert.Equal(t, Path{}, ParsePath("Objects"))
+	assert.Equal(t, "Objects/SmartFridge22334411/PowerConsumption", Path{"SmartFridge22334411", "PowerConsumption"}.String())
+}
+
+func TestPathHasPrefix(t *testing.T) {
+	p := Path{"A", "B", "Item"}
+	assert.True(t, p.HasPrefix(Path{}))
+	assert.True(t, p.HasPrefix(Path{"A", "B"}))
+	assert.False(t, p.HasPrefix(Path{"A", "C"}))
+	assert.False(t, Path{"A"}.HasPrefix(p))
+}
+
+func TestLookupByPath(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/object_object_infoitem_values.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "SubSubTarget1", v.Object(ParsePath("UniqueTargetID_1/SubTarget1/SubSubTarget1")).ID())
+			assert.Nil(t, v.Object(ParsePath("UniqueTargetID_1/Missing")))
+			item := v.InfoItem(ParsePath("UniqueTargetID_1/SubTarget2/SubTarget2InfoItem1"))
+			if assert.NotNil(t, item) {
+				assert.Equal(t, "34.6", item.Values[0].Text)
+			}
+			assert.Nil(t, v.InfoItem(ParsePath("UniqueTargetID_1/SubTarget2/Missing")))
+		}
+	}
+}
+
+func TestAddByPath(t *testing.T) {
+	objects := Objects{}
+	objects.Add(ParsePath("Fridge/Freezer/Temperature"), Value{Text: "-18"})
+	objects.Add(ParsePath("Fridge/Freezer/Temperature"), Value{Text: "-19"})
+	objects.Add(ParsePath("Fridge/Door"))
+	if assert.Len(t, objects.Objects, 1) {
+		assert.Len(t, objects.Objects[0].InfoItems, 1)
+		assert.Len(t, objects.Objects[0].Objects, 1)
+	}
+	item := objects.InfoItem(ParsePath("Fridge/Freezer/Temperature"))
+	if assert.NotNil(t, item) {
+		assert.Equal(t, []Value{Value{Text: "-18"}, Value{Text: "-19"}}, item.Values)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/object_object_infoitem_values.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			paths := []string{}
+			v.Walk(func(path Path, item *InfoItem) {
+				paths = append(paths, path.String())
+			})
+			assert.Equal(t, []string{
+				"Objects/UniqueTargetID_1/InfoItem1",
+				"Objects/UniqueTargetID_1/InfoItem2",
+				"Objects/UniqueTargetID_1/SubTarget1/SubInfoItem1",
+				"Objects/UniqueTargetID_1/SubTarget1/SubSubTarget1/SubSubTarget1InfoItem1",
+				"Objects/UniqueTargetID_1/SubTarget2/SubTarget2InfoItem1",
+			}, paths)
+		}
+	}
+}
+
+func TestAddObjectByPath(t *testing.T) {
+	objects := Objects{}
+	objects.AddObject(ParsePath("Fridge/Freezer"))
+	objects.AddObject(ParsePath("Fridge/Door"))
+	assert.Nil(t, objects.AddObject(Path{}))
+	if assert.Len(t, objects.Objects, 1) {
+		assert.Len(t, objects.Objects[0].Objects, 2)
+	}
+	assert.Equal(t, "Door", objects.Object(ParsePath("Fridge/Door")).ID())
+}
@@ -0,0 +1,71 @@
+package df
+
+// Projection selects which parts of a tree a read returns.
+type Projection int
+
+const (
+	// AllContent keeps the tree as it is.
+	AllContent Projection = iota
+	// MetaDataOnly keeps the ids and names leading to InfoItems and their MetaData.
+	MetaDataOnly
+	// DescriptionOnly keeps the ids and names and every description.
+	DescriptionOnly
+	// StructureOnly keeps only the ids, types, udefs and names of the tree.
+	StructureOnly
+)
+
+// Project returns a copy of the tree reduced to the parts selected by p. The
+// copy shares no memory with o.
+func (o Objects) Project(p Projection) Objects {
+	if p != AllContent {
+		o.Objects = projectObjects(o.Objects, p)
+	}
+	return *o.DeepCopy()
+}
+
+func (o Objects) MetaDataOnly() Objects {
+	return o.Project(MetaDataOnly)
+}
+
+func (o Objects) DescriptionOnly() Objects {
+	return o.Project(DescriptionOnly)
+}
+
+func (o Objects) StructureOnly() Objects {
+	return o.Project(StructureOnly)
+}
+
+func projectObjects(objects []Object, p Projection) []Object {
+	if objects == nil {
+		return nil
+	}
+	projected := make([]Object, len(objects))
+	for i, object := range objects {
+		if p != DescriptionOnly {
+			object.Description = nil
+			object.OtherDescriptions = nil
+		}
+		object.InfoItems = projectInfoItems(object.InfoItems, p)
+		object.Objects = projectObjects(object.Objects, p)
+		projected[i] = object
+	}
+	return projected
+}
+
+func projectInfoItems(items []InfoItem, p Projection) []InfoItem {
+	if items == nil {
+		return nil
+	}
+	projected := make([]InfoItem, len(items))
+	for i, item := range items {
+		item.Values = nil
+		if p != DescriptionOnly {
+			item.Description = nil
+		}
+		if p != MetaDataOnly {
+			item.MetaData = nil
+		}
+		projected[i] = item
+	}
+	return projected
+}
@@ -0,0 +1,71 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func loadExample(t *testing.T, name string) *Objects {
+	data, err := ioutil.ReadFile("examples/" + name)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	v, err := Unmarshal(data)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return v
+}
+
+func TestProjectMetaDataOnly(t *testing.T) {
+	v := loadExample(t, "metadata_about_refrigerator_power_consumption.xml")
+	v.Objects[0].InfoItems[0].Values = []Value{Value{Text: "43"}}
+
+	projected := v.MetaDataOnly()
+	item := projected.Objects[0].InfoItems[0]
+	assert.Equal(t, "PowerConsumption", item.Name)
+	assert.Len(t, item.Values, 0)
+	if assert.NotNil(t, item.MetaData) {
+		assert.Len(t, item.MetaData.InfoItems, 6)
+	}
+	assert.Len(t, v.Objects[0].InfoItems[0].Values, 1)
+}
+
+func TestProjectDescriptionOnly(t *testing.T) {
+	v := loadExample(t, "measurement_values_for_refrigerator_power_consumption.xml")
+
+	projected := v.DescriptionOnly()
+	item := projected.Objects[0].InfoItems[0]
+	assert.Equal(t, "SmartFridge22334411", projected.Objects[0].ID())
+	assert.Len(t, item.Values, 0)
+	if assert.NotNil(t, item.Description) {
+		assert.Equal(t, "Power consumption values with timestamp.", item.Description.Text)
+	}
+	assert.Len(t, v.Objects[0].InfoItems[0].Values, 5)
+}
+
+func TestProjectStructureOnly(t *testing.T) {
+	v := loadExample(t, "measurement_values_for_refrigerator_power_consumption.xml")
+
+	projected := v.StructureOnly()
+	item := projected.Objects[0].InfoItems[0]
+	assert.Equal(t, "Refrigerator Assembly Product", projected.Objects[0].Type)
+	assert.Equal(t, "b.o.9_1.1.14.13", item.Udef)
+	assert.Nil(t, item.Description)
+	assert.Nil(t, item.MetaData)
+	assert.Len(t, item.Values, 0)
+}
+
+func TestProjectSharesNoMemory(t *testing.T) {
+	v := loadExample(t, "metadata_about_refrigerator_power_consumption.xml")
+	for _, p := range []Projection{AllContent, MetaDataOnly, StructureOnly} {
+		projected := v.Project(p)
+		projected.Objects[0].Id.Text = "changed"
+		if projected.Objects[0].InfoItems[0].MetaData != nil {
+			projected.Objects[0].InfoItems[0].MetaData.InfoItems[0].Name = "changed"
+		}
+	}
+	assert.Equal(t, "SmartFridge22334411", v.Objects[0].Id.Text)
+	assert.Equal(t, "format", v.Objects[0].InfoItems[0].MetaData.InfoItems[0].Name)
+}
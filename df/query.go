@@ -0,0 +1,610 @@
+package df
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query selects parts of a tree with a path of steps separated by slashes,
+// for example
+//
+//	Objects/*[type = "Refrigerator"]/**/Temperature*[value > 5]
+//
+// Every step but the last matches Objects by id; the last one matches
+// InfoItems by name or whole Objects by id. A step is a name pattern in
+// which * matches any run of characters and ? a single one, or ** for any
+// number of Object levels. Names with spaces or special characters are
+// written in double quotes. A step may be followed by conditions in square
+// brackets joined with "and":
+//
+//	id, name, type, udef   compare with =, != or ~ (pattern match)
+//	value                  compares the latest value with =, !=, <, <=, >, >=
+//	time                   keeps only values in the window, e.g. time >= "2014-01-01T00:00"
+//
+// Numeric values are compared as numbers, anything else as text. Only the
+// last step can match InfoItems, so name, value and time are not allowed
+// on the others. On the last step id and type select Objects and name,
+// value and time select InfoItems; they cannot be combined.
+type Query struct {
+	steps []step
+}
+
+type step struct {
+	pattern    string
+	any        bool
+	conditions []condition
+}
+
+type condition struct {
+	pos    int
+	field  string
+	op     string
+	value  string
+	number float64
+	time   time.Time
+	isNum  bool
+}
+
+// QueryError reports a query that cannot be parsed.
+type QueryError struct {
+	Query  string
+	Column int
+	Msg    string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("df: query column %d: %s", e.Column, e.Msg)
+}
+
+var fieldOps = map[string][]string{
+	"id":    {"=", "!=", "~"},
+	"name":  {"=", "!=", "~"},
+	"type":  {"=", "!=", "~"},
+	"udef":  {"=", "!=", "~"},
+	"value": {"=", "!=", "<", "<=", ">", ">="},
+	"time":  {"<", "<=", ">", ">="},
+}
+
+func ParseQuery(s string) (*Query, error) {
+	p := &queryParser{src: s}
+	q, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Select returns the parts of objects matched by the query in s.
+func Select(objects Objects, s string) (Objects, error) {
+	q, err := ParseQuery(s)
+	if err != nil {
+		return Objects{}, err
+	}
+	return q.Select(objects), nil
+}
+
+type queryParser struct {
+	src string
+	pos int
+}
+
+func (p *queryParser) errorf(pos int, format string, args ...interface{}) error {
+	return &QueryError{Query: p.src, Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *queryParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *queryParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *queryParser) found() string {
+	if p.pos >= len(p.src) {
+		return "end of query"
+	}
+	end := p.pos + 1
+	for end < len(p.src) && !strings.ContainsRune(" \t/[]\"", rune(p.src[end])) {
+		end++
+	}
+	return strconv.Quote(p.src[p.pos:end])
+}
+
+func (p *queryParser) parse() (*Query, error) {
+	q := &Query{}
+	p.skipSpace()
+	if p.peek() == '/' {
+		p.pos++
+	}
+	for {
+		s, quoted, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		root := len(q.steps) == 0 && !quoted && s.pattern == "Objects" && len(s.conditions) == 0
+		if !root || p.peek() != '/' {
+			q.steps = append(q.steps, s)
+		}
+		p.skipSpace()
+		if p.pos == len(p.src) {
+			break
+		}
+		if p.peek() != '/' {
+			return nil, p.errorf(p.pos, "expected / or [, found %s", p.found())
+		}
+		p.pos++
+		if p.pos == len(p.src) {
+			break
+		}
+	}
+	if len(q.steps) == 0 {
+		return nil, p.errorf(p.pos, "query selects nothing")
+	}
+	if err := p.checkFields(q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+var (
+	objectFields   = map[string]bool{"id": true, "type": true}
+	infoItemFields = map[string]bool{"name": true, "value": true, "time": true}
+)
+
+// checkFields rejects conditions that can never hold for the step they
+// are on.
+func (p *queryParser) checkFields(q *Query) error {
+	for i, s := range q.steps {
+		var object, infoItem *condition
+		for j := range s.conditions {
+			c := &s.conditions[j]
+			if i < len(q.steps)-1 && infoItemFields[c.field] {
+				return p.errorf(c.pos, "%s applies to InfoItems, which only the last step matches", c.field)
+			}
+			if objectFields[c.field] && object == nil {
+				object = c
+			}
+			if infoItemFields[c.field] && infoItem == nil {
+				infoItem = c
+			}
+			if object != nil && infoItem != nil {
+				return p.errorf(c.pos, "%s applies to Objects and %s to InfoItems, a step cannot match both", object.field, infoItem.field)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *queryParser) parseStep() (step, bool, error) {
+	s := step{}
+	p.skipSpace()
+	start := p.pos
+	quoted := p.peek() == '"'
+	if quoted {
+		text, err := p.parseString()
+		if err != nil {
+			return s, quoted, err
+		}
+		s.pattern = text
+	} else {
+		for p.pos < len(p.src) && !strings.ContainsRune(" \t/[]\"", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		s.pattern = p.src[start:p.pos]
+		if s.pattern == "" {
+			return s, quoted, p.errorf(p.pos, "expected a name, found %s", p.found())
+		}
+		s.any = s.pattern == "**"
+	}
+
+	for {
+		p.skipSpace()
+		if p.peek() != '[' {
+			break
+		}
+		if s.any {
+			return s, quoted, p.errorf(p.pos, "** cannot have conditions")
+		}
+		p.pos++
+		for {
+			c, err := p.parseCondition()
+			if err != nil {
+				return s, quoted, err
+			}
+			s.conditions = append(s.conditions, c)
+			p.skipSpace()
+			if strings.HasPrefix(p.src[p.pos:], "and ") {
+				p.pos += len("and ")
+				continue
+			}
+			if p.peek() != ']' {
+				return s, quoted, p.errorf(p.pos, "expected ] or and, found %s", p.found())
+			}
+			p.pos++
+			break
+		}
+	}
+	return s, quoted, nil
+}
+
+func (p *queryParser) parseCondition() (condition, error) {
+	c := condition{}
+	p.skipSpace()
+	start := p.pos
+	c.pos = start
+	for p.pos < len(p.src) && (p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z') {
+		p.pos++
+	}
+	c.field = p.src[start:p.pos]
+	ops, ok := fieldOps[c.field]
+	if !ok {
+		p.pos = start
+		return c, p.errorf(start, "expected one of id, name, type, udef, value or time, found %s", p.found())
+	}
+
+	p.skipSpace()
+	opStart := p.pos
+	for p.pos < len(p.src) && strings.ContainsRune("=!<>~", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	c.op = p.src[opStart:p.pos]
+	if !contains(ops, c.op) {
+		p.pos = opStart
+		return c, p.errorf(opStart, "expected %s after %s, found %s", strings.Join(ops, ", "), c.field, p.found())
+	}
+
+	p.skipSpace()
+	valueStart := p.pos
+	if p.peek() == '"' {
+		text, err := p.parseString()
+		if err != nil {
+			return c, err
+		}
+		c.value = text
+	} else {
+		for p.pos < len(p.src) && !strings.ContainsRune(" \t]", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		c.value = p.src[valueStart:p.pos]
+		if c.value == "" {
+			return c, p.errorf(valueStart, "expected a value after %s %s, found %s", c.field, c.op, p.found())
+		}
+	}
+
+	if n, err := strconv.ParseFloat(c.value, 64); err == nil {
+		c.number, c.isNum = n, true
+	}
+	if c.field == "time" {
+		t, ok := Value{DateTime: c.value}.Time()
+		if !ok {
+			return c, p.errorf(valueStart, "%q is not a dateTime", c.value)
+		}
+		c.time = t
+	}
+	return c, nil
+}
+
+func (p *queryParser) parseString() (string, error) {
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		switch {
+		case ch == '"':
+			p.pos++
+			return b.String(), nil
+		case ch == '\\' && p.pos+1 < len(p.src):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		default:
+			b.WriteByte(ch)
+			p.pos++
+		}
+	}
+	return "", p.errorf(start, "unterminated string")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+type match struct {
+	chain []*Object
+	item  *InfoItem
+}
+
+// Select returns a copy of objects pruned to the matched Objects and
+// InfoItems and the Objects leading to them.
+func (q *Query) Select(objects Objects) Objects {
+	matches := []match{}
+	q.visit(&objects, nil, 0, &matches)
+
+	result := objects
+	result.Objects = nil
+	whole := map[string]bool{}
+	seen := map[string]bool{}
+	for _, m := range matches {
+		path := Path{}
+		covered := false
+		for _, o := range m.chain {
+			path = path.Child(o.ID())
+			covered = covered || whole[path.String()]
+		}
+		if m.item != nil {
+			path = path.Child(m.item.Name)
+		}
+		if covered || seen[path.String()] {
+			continue
+		}
+		seen[path.String()] = true
+
+		parent := &result.Objects
+		for i, o := range m.chain {
+			last := i == len(m.chain)-1
+			var found *Object
+			for j := range *parent {
+				if (*parent)[j].ID() == o.ID() {
+					found = &(*parent)[j]
+				}
+			}
+			if last && m.item == nil {
+				if found != nil {
+					*found = *o.DeepCopy()
+				} else {
+					*parent = append(*parent, *o.DeepCopy())
+				}
+				whole[path.String()] = true
+				break
+			}
+			if found == nil {
+				header := Object{Type: o.Type, Udef: o.Udef, Id: o.Id, OtherIds: o.OtherIds}
+				*parent = append(*parent, *header.DeepCopy())
+				found = &(*parent)[len(*parent)-1]
+			}
+			if last {
+				found.InfoItems = append(found.InfoItems, *m.item)
+			}
+			parent = &found.Objects
+		}
+	}
+	return result
+}
+
+func (q *Query) visit(root *Objects, chain []*Object, i int, matches *[]match) {
+	s := q.steps[i]
+	last := i == len(q.steps)-1
+
+	children := root.Objects
+	if len(chain) > 0 {
+		children = chain[len(chain)-1].Objects
+	}
+
+	if s.any {
+		if last {
+			for j := range children {
+				*matches = append(*matches, match{chain: extend(chain, &children[j])})
+			}
+			return
+		}
+		q.visit(root, chain, i+1, matches)
+		for j := range children {
+			q.visit(root, extend(chain, &children[j]), i, matches)
+		}
+		return
+	}
+
+	for j := range children {
+		if !s.matchObject(&children[j]) {
+			continue
+		}
+		if last {
+			*matches = append(*matches, match{chain: extend(chain, &children[j])})
+		} else {
+			q.visit(root, extend(chain, &children[j]), i+1, matches)
+		}
+	}
+
+	if last && len(chain) > 0 {
+		current := chain[len(chain)-1]
+		for j := range current.InfoItems {
+			if item, ok := s.matchInfoItem(current.InfoItems[j]); ok {
+				*matches = append(*matches, match{chain: chain, item: item})
+			}
+		}
+	}
+}
+
+func extend(chain []*Object, o *Object) []*Object {
+	extended := make([]*Object, len(chain), len(chain)+1)
+	copy(extended, chain)
+	return append(extended, o)
+}
+
+func (s step) matchObject(o *Object) bool {
+	if !matchPattern(s.pattern, o.ID()) {
+		return false
+	}
+	for _, c := range s.conditions {
+		var ok bool
+		switch c.field {
+		case "id":
+			ok = c.compareText(o.ID())
+		case "type":
+			ok = c.compareText(o.Type)
+		case "udef":
+			ok = c.compareText(o.Udef)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s step) matchInfoItem(item InfoItem) (*InfoItem, bool) {
+	if !matchPattern(s.pattern, item.Name) {
+		return nil, false
+	}
+
+	item = *item.DeepCopy()
+	windowed := false
+	for _, c := range s.conditions {
+		if c.field != "time" {
+			continue
+		}
+		windowed = true
+		values := []Value{}
+		for _, v := range item.Values {
+			if t, ok := v.Time(); ok && c.compareTime(t) {
+				values = append(values, v)
+			}
+		}
+		item.Values = values
+	}
+	if windowed && len(item.Values) == 0 {
+		return nil, false
+	}
+
+	for _, c := range s.conditions {
+		var ok bool
+		switch c.field {
+		case "name":
+			ok = c.compareText(item.Name)
+		case "udef":
+			ok = c.compareText(item.Udef)
+		case "value":
+			latest, found := latestValue(item.Values)
+			ok = found && c.compareValue(latest.Text)
+		case "time":
+			ok = true
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	return &item, true
+}
+
+func latestValue(values []Value) (Value, bool) {
+	if len(values) == 0 {
+		return Value{}, false
+	}
+	latest := values[len(values)-1]
+	latestTime, timed := latest.Time()
+	for _, v := range values {
+		if t, ok := v.Time(); ok && (!timed || t.After(latestTime)) {
+			latest, latestTime, timed = v, t, true
+		}
+	}
+	return latest, true
+}
+
+func (c condition) compareText(s string) bool {
+	switch c.op {
+	case "=":
+		return s == c.value
+	case "!=":
+		return s != c.value
+	case "~":
+		return matchPattern(c.value, s)
+	}
+	return false
+}
+
+func (c condition) compareValue(s string) bool {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || !c.isNum {
+		switch c.op {
+		case "=":
+			return s == c.value
+		case "!=":
+			return s != c.value
+		}
+		return false
+	}
+	return compareOrdered(c.op, compareFloat(n, c.number))
+}
+
+func (c condition) compareTime(t time.Time) bool {
+	cmp := 0
+	if t.Before(c.time) {
+		cmp = -1
+	} else if t.After(c.time) {
+		cmp = 1
+	}
+	return compareOrdered(c.op, cmp)
+}
+
+func compareFloat(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func compareOrdered(op string, cmp int) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// matchPattern reports whether name matches pattern, in which * matches any
+// run of characters and ? any single character.
+func matchPattern(pattern, name string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			pattern = strings.TrimLeft(pattern, "*")
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchPattern(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if name == "" {
+				return false
+			}
+			pattern, name = pattern[1:], name[1:]
+		default:
+			if name == "" || pattern[0] != name[0] {
+				return false
+			}
+			pattern, name = pattern[1:], name[1:]
+		}
+	}
+	return name == ""
+}
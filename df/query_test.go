@@ -0,0 +1,145 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func fridges() Objects {
+	return Objects{Objects: []Object{
+		Object{
+			Type: "Refrigerator",
+			Id:   &QLMID{Text: "Fridge1"},
+			InfoItems: []InfoItem{
+				InfoItem{Name: "Power", Values: []Value{Value{Text: "15.5"}}},
+			},
+			Objects: []Object{
+				Object{
+					Id: &QLMID{Text: "Freezer"},
+					InfoItems: []InfoItem{
+						InfoItem{Name: "TemperatureInside", Values: []Value{
+							Value{Text: "7.5", DateTime: "2014-01-01T00:00"},
+							Value{Text: "-18", DateTime: "2014-01-02T00:00"},
+						}},
+					},
+				},
+				Object{
+					Id: &QLMID{Text: "Cooler"},
+					InfoItems: []InfoItem{
+						InfoItem{Name: "TemperatureInside", Values: []Value{
+							Value{Text: "4.0", DateTime: "2014-01-01T00:00"},
+							Value{Text: "6.5", DateTime: "2014-01-02T00:00"},
+						}},
+						InfoItem{Name: "TemperatureOutside", Values: []Value{Value{Text: "21"}}},
+					},
+				},
+			},
+		},
+		Object{
+			Type: "Oven",
+			Id:   &QLMID{Text: "Oven1"},
+			InfoItems: []InfoItem{
+				InfoItem{Name: "TemperatureInside", Values: []Value{Value{Text: "180"}}},
+			},
+		},
+	}}
+}
+
+func selectPaths(t *testing.T, query string) []string {
+	selected, err := Select(fridges(), query)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	paths := []string{}
+	selected.Walk(func(path Path, item *InfoItem) {
+		paths = append(paths, path.String())
+	})
+	return paths
+}
+
+func TestSelectWithValueComparison(t *testing.T) {
+	assert.Equal(t, []string{
+		"Objects/Fridge1/Cooler/TemperatureInside",
+		"Objects/Fridge1/Cooler/TemperatureOutside",
+	}, selectPaths(t, `Objects/*[type = "Refrigerator"]/**/Temperature*[value > 5]`))
+}
+
+func TestSelectWithPatterns(t *testing.T) {
+	assert.Equal(t, []string{
+		"Objects/Fridge1/Freezer/TemperatureInside",
+		"Objects/Fridge1/Cooler/TemperatureInside",
+	}, selectPaths(t, `Fridge?/*/Temperature*[name ~ "*Inside"]`))
+	assert.Equal(t, []string{"Objects/Oven1/TemperatureInside"}, selectPaths(t, `/Objects/*[type != Refrigerator]/TemperatureInside`))
+	assert.Equal(t, []string{}, selectPaths(t, `Fridge1/Missing`))
+}
+
+func TestSelectWholeObjects(t *testing.T) {
+	selected, err := Select(fridges(), `Fridge1/Cooler`)
+	if assert.Nil(t, err) && assert.Len(t, selected.Objects, 1) {
+		assert.Equal(t, "Refrigerator", selected.Objects[0].Type)
+		assert.Len(t, selected.Objects[0].InfoItems, 0)
+		if assert.Len(t, selected.Objects[0].Objects, 1) {
+			assert.Len(t, selected.Objects[0].Objects[0].InfoItems, 2)
+		}
+	}
+}
+
+func TestSelectWithTimeWindow(t *testing.T) {
+	selected, err := Select(fridges(), `Fridge1/**/TemperatureInside[time >= "2014-01-02T00:00" and value < 0]`)
+	if assert.Nil(t, err) {
+		item := selected.InfoItem(ParsePath("Fridge1/Freezer/TemperatureInside"))
+		if assert.NotNil(t, item) && assert.Len(t, item.Values, 1) {
+			assert.Equal(t, "-18", item.Values[0].Text)
+		}
+		assert.Nil(t, selected.InfoItem(ParsePath("Fridge1/Cooler/TemperatureInside")))
+	}
+}
+
+func TestSelectQuotedNames(t *testing.T) {
+	v := loadExample(t, "measurement_values_for_refrigerator_power_consumption.xml")
+	selected, err := Select(*v, `SmartFridge22334411/"Consumed Electrical Power Measure"[udef = "b.o.9_1.1.14.13" and value > 15]`)
+	if assert.Nil(t, err) {
+		assert.NotNil(t, selected.InfoItem(ParsePath("SmartFridge22334411/Consumed Electrical Power Measure")))
+	}
+}
+
+func TestSelectDoesNotModifyTree(t *testing.T) {
+	objects := fridges()
+	selected, err := Select(objects, `Fridge1/**/TemperatureInside[time < 2014-01-02T00:00]`)
+	if assert.Nil(t, err) {
+		selected.InfoItem(ParsePath("Fridge1/Freezer/TemperatureInside")).Values[0].Text = "changed"
+		assert.Equal(t, "7.5", objects.InfoItem(ParsePath("Fridge1/Freezer/TemperatureInside")).Values[0].Text)
+	}
+}
+
+func TestSelectObjectsByIdOnLastStep(t *testing.T) {
+	assert.Equal(t, []string{"Objects/Fridge1/Cooler/TemperatureInside", "Objects/Fridge1/Cooler/TemperatureOutside"},
+		selectPaths(t, `Fridge1/*[id != Freezer and udef = ""]`))
+	assert.Equal(t, []string{"Objects/Oven1/TemperatureInside"}, selectPaths(t, `*[type = Oven and udef = ""]`))
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for query, expected := range map[string]string{
+		``:                                     `df: query column 1: expected a name, found end of query`,
+		`Objects/*[colour = red]`:              `df: query column 11: expected one of id, name, type, udef, value or time, found "colour"`,
+		`Objects/*[value 5]`:                   `df: query column 17: expected =, !=, <, <=, >, >= after value, found "5"`,
+		`Objects/*[type ~ ]`:                   `df: query column 18: expected a value after type ~, found "]"`,
+		`Objects/*[type = "Fridge`:             `df: query column 18: unterminated string`,
+		`Objects/*[type = a or b]`:             `df: query column 20: expected ] or and, found "or"`,
+		`Objects/**[type = a]`:                 `df: query column 11: ** cannot have conditions`,
+		`Objects/*[time > yesterday]`:          `df: query column 18: "yesterday" is not a dateTime`,
+		`Objects/* x`:                          `df: query column 11: expected / or [, found "x"`,
+		`*[name = Fridge1]/Power`:              `df: query column 3: name applies to InfoItems, which only the last step matches`,
+		`*[value > 5]/Power`:                   `df: query column 3: value applies to InfoItems, which only the last step matches`,
+		`*[time > 2014-01-01T00:00:00Z]/Power`: `df: query column 3: time applies to InfoItems, which only the last step matches`,
+		`Fridge1/*[type = a and value > 5]`:    `df: query column 24: type applies to Objects and value to InfoItems, a step cannot match both`,
+		`Fridge1/*[name = Power and id = x]`:   `df: query column 28: id applies to Objects and name to InfoItems, a step cannot match both`,
+	} {
+		_, err := ParseQuery(query)
+		if assert.NotNil(t, err, query) {
+			assert.Equal(t, expected, err.Error(), query)
+			_, ok := err.(*QueryError)
+			assert.True(t, ok)
+		}
+	}
+}
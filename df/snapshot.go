@@ -0,0 +1,44 @@
+package df
+
+import "sync"
+
+// Snapshot is an immutable copy of a tree. It is safe for concurrent use;
+// everything it returns is a copy that the caller may modify.
+type Snapshot struct {
+	objects Objects
+
+	hashOnce sync.Once
+	hashes   *HashTree
+}
+
+func NewSnapshot(objects Objects) *Snapshot {
+	return &Snapshot{objects: *objects.DeepCopy()}
+}
+
+func (s *Snapshot) Objects() Objects {
+	return *s.objects.DeepCopy()
+}
+
+func (s *Snapshot) Object(path Path) *Object {
+	return s.objects.Object(path).DeepCopy()
+}
+
+func (s *Snapshot) InfoItem(path Path) *InfoItem {
+	return s.objects.InfoItem(path).DeepCopy()
+}
+
+// Walk calls fn with a copy of every InfoItem in the snapshot.
+func (s *Snapshot) Walk(fn func(path Path, item InfoItem)) {
+	s.objects.Walk(func(path Path, item *InfoItem) {
+		fn(path, *item.DeepCopy())
+	})
+}
+
+// Hashes returns the hash tree of the snapshot, computed on first use. It
+// is shared between callers and must not be modified.
+func (s *Snapshot) Hashes() *HashTree {
+	s.hashOnce.Do(func() {
+		s.hashes = Hashes(s.objects)
+	})
+	return s.hashes
+}
@@ -0,0 +1,47 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIsIsolated(t *testing.T) {
+	v := loadExample(t, "object_object_infoitem_values.xml")
+	s := NewSnapshot(*v)
+
+	v.Objects[0].Id.Text = "changed"
+	object := s.Object(ParsePath("UniqueTargetID_1"))
+	if assert.NotNil(t, object) {
+		object.InfoItems[0].Values[0].Text = "changed"
+	}
+
+	item := s.InfoItem(ParsePath("UniqueTargetID_1/InfoItem1"))
+	if assert.NotNil(t, item) {
+		assert.Equal(t, "Value1", item.Values[0].Text)
+	}
+	objects := s.Objects()
+	assert.Equal(t, "UniqueTargetID_1", objects.Objects[0].ID())
+	assert.Nil(t, s.InfoItem(ParsePath("UniqueTargetID_1/Missing")))
+}
+
+func TestSnapshotConcurrentReaders(t *testing.T) {
+	s := NewSnapshot(*loadExample(t, "object_object_infoitem_values.xml"))
+	expected := Sum(s.Objects())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count := 0
+			s.Walk(func(path Path, item InfoItem) {
+				item.Values = nil
+				count++
+			})
+			assert.Equal(t, 5, count)
+			assert.Equal(t, expected, s.Hashes().Hash)
+		}()
+	}
+	wg.Wait()
+}
@@ -1,5 +1,7 @@
 package df
 
+//go:generate go run ../internal/deepcopygen -o deepcopy_generated.go structs.go
+
 type Objects struct {
 	Objects                   []Object `xml:"Object"`
 	XmlnsXsi                  string   `xml:"xmlns:xsi,attr"`
@@ -7,13 +9,18 @@ type Objects struct {
 	Version                   string   `xml:"version,attr,omitempty"`
 }
 
+// Object keeps its first id and description in Id and Description and any
+// further ones in OtherIds and OtherDescriptions; see object.go for the XML
+// mapping.
 type Object struct {
-	Type        string       `xml:"type,attr,omitempty"`
-	Udef        string       `xml:"udef,attr,omitempty"`
-	Id          *QLMID       `xml:"id"`
-	Description *Description `xml:"description"`
-	InfoItems   []InfoItem   `xml:"InfoItem"`
-	Objects     []Object     `xml:"Object"`
+	Type              string        `xml:"type,attr,omitempty"`
+	Udef              string        `xml:"udef,attr,omitempty"`
+	Id                *QLMID        `xml:"-"`
+	OtherIds          []QLMID       `xml:"-"`
+	Description       *Description  `xml:"-"`
+	OtherDescriptions []Description `xml:"-"`
+	InfoItems         []InfoItem    `xml:"InfoItem"`
+	Objects           []Object      `xml:"Object"`
 }
 
 type InfoItem struct {
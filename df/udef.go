@@ -0,0 +1,189 @@
+package df
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UDEF is a Universal Data Element Framework code such as "b.o.9_1.1.14.13".
+// The part before the underscore names an object class and the part after
+// it a property. Both are read from right to left: the rightmost segment is
+// the broadest category and every segment to its left narrows it down.
+type UDEF struct {
+	Object   []string
+	Property []string
+}
+
+func ParseUDEF(s string) (UDEF, error) {
+	u := UDEF{}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return u, fmt.Errorf("df: empty UDEF code")
+	}
+	object, property := s, ""
+	if i := strings.Index(s, "_"); i >= 0 {
+		object, property = s[:i], s[i+1:]
+		if property == "" {
+			return u, fmt.Errorf("df: UDEF code %q has an empty property", s)
+		}
+	}
+
+	var err error
+	if u.Object, err = parseUDEFPart(s, object); err != nil {
+		return u, err
+	}
+	if property != "" {
+		if u.Property, err = parseUDEFPart(s, property); err != nil {
+			return u, err
+		}
+	}
+	return u, nil
+}
+
+func parseUDEFPart(code, part string) ([]string, error) {
+	segments := strings.Split(part, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("df: UDEF code %q has an empty segment", code)
+		}
+		if !isUDEFSegment(segment) {
+			return nil, fmt.Errorf("df: UDEF code %q has invalid segment %q", code, segment)
+		}
+	}
+	return segments, nil
+}
+
+// isUDEFSegment reports whether segment is all lower case letters or all digits.
+func isUDEFSegment(segment string) bool {
+	letters, digits := 0, 0
+	for _, r := range segment {
+		switch {
+		case r >= 'a' && r <= 'z':
+			letters++
+		case r >= '0' && r <= '9':
+			digits++
+		default:
+			return false
+		}
+	}
+	return letters == 0 || digits == 0
+}
+
+func (u UDEF) String() string {
+	s := strings.Join(u.Object, ".")
+	if len(u.Property) > 0 {
+		s += "_" + strings.Join(u.Property, ".")
+	}
+	return s
+}
+
+// IsA reports whether u belongs to class: its object class is class's
+// object class or a narrower one, and likewise for the property if class
+// has one.
+func (u UDEF) IsA(class UDEF) bool {
+	if !hasSuffix(u.Object, class.Object) {
+		return false
+	}
+	return len(class.Property) == 0 || hasSuffix(u.Property, class.Property)
+}
+
+// Parent returns the next broader code: the property with its leftmost
+// segment removed, or the object class once there is no property left.
+// The parent of a single segment object class is the zero UDEF.
+func (u UDEF) Parent() UDEF {
+	switch {
+	case len(u.Property) > 1:
+		return UDEF{Object: u.Object, Property: u.Property[1:]}
+	case len(u.Property) == 1:
+		return UDEF{Object: u.Object}
+	case len(u.Object) > 1:
+		return UDEF{Object: u.Object[1:]}
+	}
+	return UDEF{}
+}
+
+func hasSuffix(segments, suffix []string) bool {
+	if len(suffix) > len(segments) {
+		return false
+	}
+	offset := len(segments) - len(suffix)
+	for i := range suffix {
+		if segments[offset+i] != suffix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// UDEFIndex maps the udef attributes of the Objects and InfoItems of a tree
+// to their paths. The udef of an Object's id counts as the Object's.
+type UDEFIndex struct {
+	entries []udefEntry
+	// Invalid lists the paths whose udef attribute could not be parsed.
+	Invalid []Path
+}
+
+type udefEntry struct {
+	udef UDEF
+	path Path
+}
+
+func NewUDEFIndex(objects Objects) *UDEFIndex {
+	ix := &UDEFIndex{}
+	var index func(parent Path, objects []Object)
+	index = func(parent Path, objects []Object) {
+		for i := range objects {
+			o := &objects[i]
+			path := parent.Child(o.ID())
+			ix.add(path, o.Udef)
+			if o.Id != nil && o.Id.Udef != o.Udef {
+				ix.add(path, o.Id.Udef)
+			}
+			for _, item := range o.InfoItems {
+				ix.add(path.Child(item.Name), item.Udef)
+			}
+			index(path, o.Objects)
+		}
+	}
+	index(Path{}, objects.Objects)
+	return ix
+}
+
+func (ix *UDEFIndex) add(path Path, code string) {
+	if code == "" {
+		return
+	}
+	u, err := ParseUDEF(code)
+	if err != nil {
+		ix.Invalid = append(ix.Invalid, path)
+		return
+	}
+	ix.entries = append(ix.entries, udefEntry{u, path})
+}
+
+// Find returns the paths of the Objects and InfoItems whose udef IsA class,
+// sorted.
+func (ix *UDEFIndex) Find(class UDEF) []Path {
+	paths := []Path{}
+	seen := map[string]bool{}
+	for _, e := range ix.entries {
+		if e.udef.IsA(class) && !seen[e.path.String()] {
+			seen[e.path.String()] = true
+			paths = append(paths, e.path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].String() < paths[j].String()
+	})
+	return paths
+}
+
+// Classes returns every distinct code in the index with its number of uses.
+func (ix *UDEFIndex) Classes() map[string]int {
+	classes := map[string]int{}
+	for _, e := range ix.entries {
+		classes[e.udef.String()]++
+	}
+	return classes
+}
@@ -0,0 +1,90 @@
+package df
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseUDEF(t *testing.T) {
+	u, err := ParseUDEF("b.o.9_1.1.14.13")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"b", "o", "9"}, u.Object)
+		assert.Equal(t, []string{"1", "1", "14", "13"}, u.Property)
+		assert.Equal(t, "b.o.9_1.1.14.13", u.String())
+	}
+
+	u, err = ParseUDEF("appropriate.udef.code")
+	if assert.Nil(t, err) {
+		assert.Len(t, u.Property, 0)
+	}
+
+	for _, code := range []string{"", "b..9", "b.o.9_", "_1.1", "B.o", "b1.o", "b.o_1_2"} {
+		_, err := ParseUDEF(code)
+		assert.NotNil(t, err, code)
+	}
+}
+
+func TestUDEFIsA(t *testing.T) {
+	u, _ := ParseUDEF("b.o.9_1.1.14.13")
+	for code, expected := range map[string]bool{
+		"9":               true,
+		"o.9":             true,
+		"b.o.9":           true,
+		"b.o.9_13":        true,
+		"b.o.9_14.13":     true,
+		"o.9_2.1.14.13":   false,
+		"a.o.9":           false,
+		"b":               false,
+		"b.o.9_2.14.13":   false,
+		"c.b.o.9_14.13":   false,
+		"b.o.9_1.1.14.13": true,
+	} {
+		class, err := ParseUDEF(code)
+		if assert.Nil(t, err, code) {
+			assert.Equal(t, expected, u.IsA(class), code)
+		}
+	}
+}
+
+func TestUDEFParent(t *testing.T) {
+	u, _ := ParseUDEF("b.o.9_14.13")
+	assert.Equal(t, "b.o.9_13", u.Parent().String())
+	assert.Equal(t, "b.o.9", u.Parent().Parent().String())
+	assert.Equal(t, "o.9", u.Parent().Parent().Parent().String())
+	assert.Equal(t, "", UDEF{Object: []string{"9"}}.Parent().String())
+}
+
+func TestUDEFIndex(t *testing.T) {
+	objects := Objects{Objects: []Object{
+		Object{
+			Udef: "b.o.9",
+			Id:   &QLMID{Text: "Fridge", Udef: "e.9"},
+			InfoItems: []InfoItem{
+				InfoItem{Name: "Power", Udef: "b.o.9_1.1.14.13"},
+				InfoItem{Name: "Setpoint", Udef: "b.o.9_2.14.13"},
+				InfoItem{Name: "Broken", Udef: "Not A Code"},
+			},
+			Objects: []Object{
+				Object{Udef: "c.o.9", Id: &QLMID{Text: "Freezer"}},
+			},
+		},
+	}}
+
+	ix := NewUDEFIndex(objects)
+	o9, _ := ParseUDEF("o.9")
+	assert.Equal(t, []Path{
+		ParsePath("Fridge"),
+		ParsePath("Fridge/Freezer"),
+		ParsePath("Fridge/Power"),
+		ParsePath("Fridge/Setpoint"),
+	}, ix.Find(o9))
+
+	power, _ := ParseUDEF("b.o.9_14.13")
+	assert.Equal(t, []Path{ParsePath("Fridge/Power"), ParsePath("Fridge/Setpoint")}, ix.Find(power))
+
+	e9, _ := ParseUDEF("e.9")
+	assert.Equal(t, []Path{ParsePath("Fridge")}, ix.Find(e9))
+
+	assert.Equal(t, []Path{ParsePath("Fridge/Broken")}, ix.Invalid)
+	assert.Equal(t, 1, ix.Classes()["b.o.9"])
+}
@@ -1,13 +1,35 @@
 package df
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"github.com/qlm-iot/qlm/internal/xmlscan"
+)
+
+// DecodeError is the error returned by Unmarshal. It gives the line,
+// column and element path of the failure, and the attribute if one was at
+// fault; use errors.As to get at it.
+type DecodeError = xmlscan.DecodeError
+
+// Decoder unmarshals O-DF documents that are within its Limits.
+type Decoder struct {
+	Limits Limits
+}
+
+func (d Decoder) Unmarshal(data []byte) (*Objects, error) {
+	if err := d.Limits.scan(data); err != nil {
+		return nil, err
+	}
 
-func Unmarshal(data []byte) (*Objects, error) {
 	v := &Objects{}
 
 	if err := xml.Unmarshal(data, v); err != nil {
-		return nil, err
+		return nil, xmlscan.Wrap(err)
 	}
 
 	return v, nil
 }
+
+// Unmarshal decodes an O-DF document within DefaultLimits.
+func Unmarshal(data []byte) (*Objects, error) {
+	return Decoder{Limits: DefaultLimits}.Unmarshal(data)
+}
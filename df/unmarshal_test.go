@@ -1,6 +1,7 @@
 package df
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"testing"
@@ -13,6 +14,32 @@ func TestUnmarshalWithInvalidXML(t *testing.T) {
 	assert.Nil(t, v)
 }
 
+func TestUnmarshalErrorPosition(t *testing.T) {
+	data := `<Objects>
+    <Object>
+        <id>SmartFridge22334411</id>
+        <InfoItem name="PowerConsumption">
+            <value unixTime="yesterday">43</value>
+        </InfoItem>
+    </Object>
+</Objects>`
+	_, err := Unmarshal([]byte(data))
+	var decodeErr *DecodeError
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, 5, decodeErr.Line)
+		assert.Equal(t, 13, decodeErr.Column)
+		assert.Equal(t, "Objects/Object/InfoItem/value", decodeErr.Path)
+		assert.Equal(t, "unixTime", decodeErr.Attr)
+		assert.EqualError(t, err, `5:13: Objects/Object/InfoItem/value@unixTime: invalid integer "yesterday": invalid syntax`)
+	}
+
+	_, err = Unmarshal([]byte("<Objects>\n<Object></Objects>"))
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, 2, decodeErr.Line)
+		assert.Equal(t, "Objects/Object", decodeErr.Path)
+	}
+}
+
 func TestUnmarshalWithoutObjects(t *testing.T) {
 	data := `
 		<?xml version="1.0" encoding="UTF-8"?>
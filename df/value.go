@@ -0,0 +1,31 @@
+package df
+
+import (
+	"strings"
+	"time"
+)
+
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// Time returns the timestamp of the value from its unixTime or dateTime
+// attribute. Dates without a zone are taken as UTC.
+func (v Value) Time() (time.Time, bool) {
+	if v.UnixTime != 0 {
+		return time.Unix(v.UnixTime, 0).UTC(), true
+	}
+	return parseDateTime(v.DateTime)
+}
+
+func parseDateTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func generateFrom(t *testing.T, src string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "deepcopygen")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "structs.go")
+	if !assert.Nil(t, ioutil.WriteFile(name, []byte(src), 0644)) {
+		t.FailNow()
+	}
+	return generate([]string{name})
+}
+
+func TestGenerate(t *testing.T) {
+	out, err := generateFrom(t, `package p
+
+type A struct {
+	Name  string
+	B     *B
+	Bs    []B
+	Tags  []string
+	Inner B
+}
+
+type B struct {
+	N int
+}
+`)
+	if assert.Nil(t, err) {
+		src := string(out)
+		assert.True(t, strings.Contains(src, "package p\n"))
+		assert.True(t, strings.Contains(src, "func (in *A) DeepCopy() *A {"))
+		assert.True(t, strings.Contains(src, "out.B = in.B.DeepCopy()"))
+		assert.True(t, strings.Contains(src, "in.Bs[i].DeepCopyInto(&out.Bs[i])"))
+		assert.True(t, strings.Contains(src, "copy(out.Tags, in.Tags)"))
+		assert.True(t, strings.Contains(src, "in.Inner.DeepCopyInto(&out.Inner)"))
+		assert.True(t, strings.Contains(src, "func (in *B) DeepCopyInto(out *B) {"))
+	}
+}
+
+func TestGenerateWithUnsupportedField(t *testing.T) {
+	_, err := generateFrom(t, `package p
+
+type A struct {
+	M map[string]string
+}
+`)
+	assert.NotNil(t, err)
+}
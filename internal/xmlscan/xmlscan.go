@@ -0,0 +1,192 @@
+// Package xmlscan checks XML documents before they are decoded with
+// encoding/xml: it enforces size limits, lets the caller validate elements
+// and attributes, and reports failures with their position and element
+// path.
+package xmlscan
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrTooLarge    = errors.New("document too large")
+	ErrTooDeep     = errors.New("elements nested too deeply")
+	ErrAttrTooLong = errors.New("attribute value too long")
+)
+
+// Limits are the limits every document is checked against. Zero means no
+// limit.
+type Limits struct {
+	MaxBytes   int64
+	MaxDepth   int
+	MaxAttrLen int
+}
+
+// LimitError reports a limit that a document exceeded. It wraps one of the
+// Err values of this package or of the package that set the limit.
+type LimitError struct {
+	Err    error
+	Limit  int64
+	Offset int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v (limit %d) at offset %d", e.Err, e.Limit, e.Offset)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError locates a decoding failure. Line and Column are 1-based and
+// zero when unknown; Path is the slash separated names of the open
+// elements and Attr the offending attribute, if any.
+type DecodeError struct {
+	Line   int
+	Column int
+	Path   string
+	Attr   string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", e.Line, e.Column)
+	}
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		if e.Attr != "" {
+			b.WriteString("@" + e.Attr)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns err as a DecodeError unless it already is one.
+func Wrap(err error) error {
+	var decodeErr *DecodeError
+	if err == nil || errors.As(err, &decodeErr) {
+		return err
+	}
+	return &DecodeError{Err: err}
+}
+
+// Scanner walks the elements of a document and keeps the stack of the
+// elements that are open.
+type Scanner struct {
+	Decoder *xml.Decoder
+	Stack   []xml.StartElement
+
+	line, column int
+}
+
+// Path returns the names of the open elements separated by slashes.
+func (s *Scanner) Path() string {
+	names := make([]string, len(s.Stack))
+	for i, start := range s.Stack {
+		names[i] = start.Name.Local
+	}
+	return strings.Join(names, "/")
+}
+
+// Fail returns a DecodeError for err at the start of the current token.
+// attr may be empty.
+func (s *Scanner) Fail(attr string, err error) error {
+	return &DecodeError{Line: s.line, Column: s.column, Path: s.Path(), Attr: attr, Err: err}
+}
+
+// Exceeded returns a DecodeError wrapping a LimitError for err.
+func (s *Scanner) Exceeded(err error, limit int) error {
+	return s.Fail("", &LimitError{Err: err, Limit: int64(limit), Offset: s.Decoder.InputOffset()})
+}
+
+// CheckInt and CheckFloat fail unless the attribute name of start, when
+// present, parses like encoding/xml parses it into a field of that size.
+func (s *Scanner) CheckInt(start xml.StartElement, name string, bits int) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			if _, err := strconv.ParseInt(strings.TrimSpace(attr.Value), 10, bits); err != nil {
+				return s.Fail(name, fmt.Errorf("invalid integer %q: %w", attr.Value, err.(*strconv.NumError).Err))
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Scanner) CheckFloat(start xml.StartElement, name string, bits int) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(attr.Value), bits); err != nil {
+				return s.Fail(name, fmt.Errorf("invalid number %q: %w", attr.Value, err.(*strconv.NumError).Err))
+			}
+		}
+	}
+	return nil
+}
+
+// CheckSize fails when data is longer than max bytes; zero means no limit.
+func CheckSize(data []byte, max int64) error {
+	if max > 0 && int64(len(data)) > max {
+		return &DecodeError{Err: &LimitError{Err: ErrTooLarge, Limit: max, Offset: max}}
+	}
+	return nil
+}
+
+// Scan checks data against limits and calls visit with every start and end
+// element; on a start element it is already on the stack. Scan stops at the
+// first error from the decoder or from visit. Syntax errors are returned as
+// DecodeErrors.
+func Scan(data []byte, limits Limits, visit func(s *Scanner, token xml.Token) error) error {
+	if err := CheckSize(data, limits.MaxBytes); err != nil {
+		return err
+	}
+	s := &Scanner{Decoder: xml.NewDecoder(bytes.NewReader(data))}
+	for {
+		s.line, s.column = s.Decoder.InputPos()
+		token, err := s.Decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			line, column := s.Decoder.InputPos()
+			return &DecodeError{Line: line, Column: column, Path: s.Path(), Err: err}
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			s.Stack = append(s.Stack, t)
+			if limits.MaxDepth > 0 && len(s.Stack) > limits.MaxDepth {
+				return s.Exceeded(ErrTooDeep, limits.MaxDepth)
+			}
+			for _, attr := range t.Attr {
+				if limits.MaxAttrLen > 0 && len(attr.Value) > limits.MaxAttrLen {
+					return s.Fail(attr.Name.Local, &LimitError{Err: ErrAttrTooLong, Limit: int64(limits.MaxAttrLen), Offset: s.Decoder.InputOffset()})
+				}
+			}
+			if visit != nil {
+				if err := visit(s, t); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if visit != nil {
+				if err := visit(s, t); err != nil {
+					return err
+				}
+			}
+			s.Stack = s.Stack[:len(s.Stack)-1]
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package xmlscan
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestScanLimits(t *testing.T) {
+	doc := `<a x="12345"><b><c/></b></a>`
+	assert.Nil(t, Scan([]byte(doc), Limits{MaxBytes: int64(len(doc)), MaxDepth: 3, MaxAttrLen: 5}, nil))
+
+	err := Scan([]byte(doc), Limits{MaxBytes: 10}, nil)
+	assert.True(t, errors.Is(err, ErrTooLarge))
+
+	err = Scan([]byte(doc), Limits{MaxDepth: 2}, nil)
+	if assert.True(t, errors.Is(err, ErrTooDeep)) {
+		var limit *LimitError
+		if assert.True(t, errors.As(err, &limit)) {
+			assert.Equal(t, int64(2), limit.Limit)
+			assert.Equal(t, int64(20), limit.Offset)
+		}
+		assert.Equal(t, "1:17: a/b/c: elements nested too deeply (limit 2) at offset 20", err.Error())
+	}
+
+	err = Scan([]byte(doc), Limits{MaxAttrLen: 4}, nil)
+	if assert.True(t, errors.Is(err, ErrAttrTooLong)) {
+		assert.Equal(t, "x", err.(*DecodeError).Attr)
+	}
+}
+
+func TestScanSyntaxError(t *testing.T) {
+	err := Scan([]byte("<a>\n  <b></a>"), Limits{}, nil)
+	var decodeErr *DecodeError
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, 2, decodeErr.Line)
+		assert.Equal(t, 10, decodeErr.Column)
+		assert.Equal(t, "a/b", decodeErr.Path)
+		var syntax *xml.SyntaxError
+		assert.True(t, errors.As(err, &syntax))
+	}
+}
+
+func TestCheckNumbers(t *testing.T) {
+	doc := "<a>\n<b n=\"1\" f=\" 2.5 \"/>\n<c n=\"x\"/><d f=\"-\"/><e n=\"300\"/></a>"
+	var failures []string
+	Scan([]byte(doc), Limits{}, func(s *Scanner, token xml.Token) error {
+		if start, ok := token.(xml.StartElement); ok {
+			for _, err := range []error{s.CheckInt(start, "n", 8), s.CheckFloat(start, "f", 64)} {
+				if err != nil {
+					failures = append(failures, err.Error())
+				}
+			}
+		}
+		return nil
+	})
+	assert.Equal(t, []string{
+		`3:1: a/c@n: invalid integer "x": invalid syntax`,
+		`3:11: a/d@f: invalid number "-": invalid syntax`,
+		`3:21: a/e@n: invalid integer "300": value out of range`,
+	}, failures)
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil))
+	err := errors.New("boom")
+	wrapped := Wrap(err)
+	assert.Equal(t, &DecodeError{Err: err}, wrapped)
+	assert.Equal(t, wrapped, Wrap(wrapped))
+	assert.Equal(t, "boom", wrapped.Error())
+}
+
+func TestScanVisit(t *testing.T) {
+	var paths []string
+	err := Scan([]byte(`<a><b/><c>text</c></a>`), Limits{}, func(s *Scanner, token xml.Token) error {
+		if _, ok := token.(xml.StartElement); ok {
+			names := []string{}
+			for _, start := range s.Stack {
+				names = append(names, start.Name.Local)
+			}
+			paths = append(paths, strings.Join(names, "/"))
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "a/b", "a/c"}, paths)
+
+	stop := errors.New("stop")
+	err = Scan([]byte(`<a/>`), Limits{}, func(s *Scanner, token xml.Token) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+}
@@ -0,0 +1,51 @@
+package mi
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// Sender delivers an envelope to an O-MI node and returns the node's reply.
+type Sender interface {
+	Send(node string, envelope OmiEnvelope) (*OmiEnvelope, error)
+}
+
+// Client is a Sender that posts envelopes to O-MI nodes over HTTP.
+type Client struct {
+	HTTPClient *http.Client
+}
+
+func (c *Client) Send(node string, envelope OmiEnvelope) (*OmiEnvelope, error) {
+	data, err := Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Post(node, "text/xml; charset=utf-8", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := Unmarshal(body)
+	if err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("mi: %s responded with %s", node, resp.Status)
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
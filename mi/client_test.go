@@ -0,0 +1,39 @@
+package mi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		v, err := Unmarshal(body)
+		if assert.Nil(t, err) && assert.NotNil(t, v.Read) {
+			data, _ := Marshal(*okReply("pong"))
+			w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{}
+	reply, err := client.Send(server.URL, OmiEnvelope{Version: "1.0", Read: &ReadRequest{}})
+	if assert.Nil(t, err) && assert.Len(t, reply.Response.Results, 1) {
+		assert.Equal(t, "pong", reply.Response.Results[0].Message.Data)
+	}
+}
+
+func TestClientSendWithHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &Client{}
+	reply, err := client.Send(server.URL, OmiEnvelope{Version: "1.0", Read: &ReadRequest{}})
+	assert.NotNil(t, err)
+	assert.Nil(t, reply)
+}
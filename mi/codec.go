@@ -0,0 +1,190 @@
+package mi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"io"
+	"strings"
+	"sync"
+)
+
+// A Codec converts the contents of a msg element of one msgformat to and
+// from a typed value.
+type Codec interface {
+	Decode(data []byte) (interface{}, error)
+	Encode(v interface{}) ([]byte, error)
+}
+
+// Raw is the payload of a msg whose msgformat has no registered codec.
+type Raw struct {
+	Format string
+	Data   string
+}
+
+// Records is the payload of a CSV msg.
+type Records [][]string
+
+var ErrNoMessage = errors.New("mi: no msg")
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{}
+)
+
+func init() {
+	Register("odf", odfCodec{})
+	Register("csv", csvCodec{})
+}
+
+// Register makes codec available for format. Formats are matched case
+// insensitively, and an empty msgformat or omi.xsd, which the examples of
+// the standard use for O-DF payloads, means odf.
+func Register(format string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	if codec == nil {
+		delete(codecs, formatKey(format))
+		return
+	}
+	codecs[formatKey(format)] = codec
+}
+
+func Lookup(format string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[formatKey(format)]
+	return codec, ok
+}
+
+func formatKey(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || format == "omi.xsd" {
+		return "odf"
+	}
+	return format
+}
+
+// DecodePayload decodes message with the codec registered for format, or
+// returns it as Raw if there is none.
+func DecodePayload(format string, message *Message) (interface{}, error) {
+	if message == nil {
+		return nil, ErrNoMessage
+	}
+	codec, ok := Lookup(format)
+	if !ok {
+		return Raw{Format: format, Data: message.Data}, nil
+	}
+	v, err := codec.Decode([]byte(message.Data))
+	if err != nil {
+		return nil, fmt.Errorf("mi: decoding %s msg: %v", formatKey(format), err)
+	}
+	return v, nil
+}
+
+// EncodePayload builds a msg from v with the codec registered for format.
+// Raw values are used as they are.
+func EncodePayload(format string, v interface{}) (*Message, error) {
+	if raw, ok := v.(Raw); ok {
+		return &Message{Data: raw.Data}, nil
+	}
+	codec, ok := Lookup(format)
+	if !ok {
+		return nil, fmt.Errorf("mi: no codec for msgformat %q", format)
+	}
+	data, err := codec.Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("mi: encoding %s msg: %v", formatKey(format), err)
+	}
+	return &Message{Data: string(data)}, nil
+}
+
+func (r RequestResult) Payload() (interface{}, error) {
+	return DecodePayload(r.MsgFormat, r.Message)
+}
+
+func (r ReadRequest) Payload() (interface{}, error) {
+	return DecodePayload(r.MsgFormat, r.Message)
+}
+
+func (r WriteRequest) Payload() (interface{}, error) {
+	return DecodePayload(r.MsgFormat, r.Message)
+}
+
+type odfCodec struct{}
+
+func (odfCodec) Decode(data []byte) (interface{}, error) {
+	return df.Unmarshal(data)
+}
+
+func (odfCodec) Encode(v interface{}) ([]byte, error) {
+	switch objects := v.(type) {
+	case df.Objects:
+		return df.Marshal(objects)
+	case *df.Objects:
+		return df.Marshal(*objects)
+	}
+	return nil, fmt.Errorf("cannot encode %T as odf", v)
+}
+
+type csvCodec struct{}
+
+func (csvCodec) Decode(data []byte) (interface{}, error) {
+	text, err := charData(data)
+	if err != nil {
+		return nil, err
+	}
+	r := csv.NewReader(strings.NewReader(strings.TrimSpace(text)))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	return Records(records), nil
+}
+
+func (csvCodec) Encode(v interface{}) ([]byte, error) {
+	var records [][]string
+	switch r := v.(type) {
+	case Records:
+		records = r
+	case [][]string:
+		records = r
+	default:
+		return nil, fmt.Errorf("cannot encode %T as CSV", v)
+	}
+	var text bytes.Buffer
+	w := csv.NewWriter(&text)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return []byte(textEscaper.Replace(text.String())), nil
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// charData returns the text content of an innerxml string with entities
+// and CDATA sections resolved.
+func charData(data []byte) (string, error) {
+	var text strings.Builder
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return text.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			return "", fmt.Errorf("unexpected element <%s> in text payload", t.Name.Local)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package mi
+
+import (
+	"errors"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDecodeMultiplePayloads(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/multiple_payload_response.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) && assert.Len(t, v.Response.Results, 3) {
+			obix, err := v.Response.Results[0].Payload()
+			if assert.Nil(t, err) && assert.IsType(t, Raw{}, obix) {
+				assert.Equal(t, "obix", obix.(Raw).Format)
+				assert.Contains(t, obix.(Raw).Data, `<bool name="furnaceOn" val="true"/>`)
+			}
+
+			csv, err := v.Response.Results[1].Payload()
+			if assert.Nil(t, err) {
+				assert.Equal(t, Records{{"11", "22", "33"}, {"44", "55", "66"}}, csv)
+			}
+
+			odf, err := v.Response.Results[2].Payload()
+			if assert.Nil(t, err) && assert.IsType(t, &df.Objects{}, odf) {
+				item := odf.(*df.Objects).InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption"))
+				if assert.NotNil(t, item) && assert.Len(t, item.Values, 1) {
+					assert.Equal(t, "43", item.Values[0].Text)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeRequestPayloads(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/write_request.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			payload, err := v.Write.Payload()
+			assert.Nil(t, err)
+			assert.IsType(t, &df.Objects{}, payload)
+		}
+	}
+
+	_, err = ReadRequest{}.Payload()
+	assert.Equal(t, ErrNoMessage, err)
+
+	_, err = ReadRequest{Message: &Message{Data: "<Objects"}}.Payload()
+	assert.NotNil(t, err)
+}
+
+func TestEncodeCSVPayload(t *testing.T) {
+	records := Records{{"a&b", "<c>"}, {"1", "2,3"}}
+	message, err := EncodePayload("CSV", records)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "a&amp;b,&lt;c&gt;\n1,\"2,3\"\n", message.Data)
+		decoded, err := DecodePayload("csv", message)
+		assert.Nil(t, err)
+		assert.Equal(t, records, decoded)
+	}
+
+	_, err = EncodePayload("csv", 42)
+	assert.NotNil(t, err)
+	_, err = EncodePayload("unknown", records)
+	assert.NotNil(t, err)
+
+	message, err = EncodePayload("unknown", Raw{Data: "<x/>"})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "<x/>", message.Data)
+	}
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Decode(data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty")
+	}
+	return string(data) + "!", nil
+}
+
+func (upperCodec) Encode(v interface{}) ([]byte, error) {
+	return []byte(v.(string)), nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	Register("test", upperCodec{})
+	defer Register("test", nil)
+
+	codec, ok := Lookup("TEST")
+	assert.True(t, ok)
+	assert.Equal(t, upperCodec{}, codec)
+
+	v, err := RequestResult{MsgFormat: "Test", Message: &Message{Data: "hi"}}.Payload()
+	assert.Nil(t, err)
+	assert.Equal(t, "hi!", v)
+
+	_, err = RequestResult{MsgFormat: "test", Message: &Message{}}.Payload()
+	assert.EqualError(t, err, "mi: decoding test msg: empty")
+
+	Register("test", nil)
+	v, err = RequestResult{MsgFormat: "test", Message: &Message{Data: "hi"}}.Payload()
+	assert.Nil(t, err)
+	assert.Equal(t, Raw{Format: "test", Data: "hi"}, v)
+}
+
+func TestOmiXsdFormatIsODF(t *testing.T) {
+	v, err := RequestResult{MsgFormat: "omi.xsd", Message: &Message{Data: `<Objects><Object><id>SmartFridge22334411</id></Object></Objects>`}}.Payload()
+	if assert.Nil(t, err) && assert.IsType(t, &df.Objects{}, v) {
+		assert.Equal(t, "SmartFridge22334411", v.(*df.Objects).Objects[0].ID())
+	}
+}
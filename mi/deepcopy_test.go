@@ -0,0 +1,32 @@
+package mi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDeepCopySharesNoMemory(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/read_request_with_nodes.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			c := v.DeepCopy()
+			assert.Equal(t, v, c)
+
+			c.Read.NodeList.Nodes[0] = "changed"
+			c.Read.Message.Data = "changed"
+			assert.Equal(t, "http://192.168.0.1/", v.Read.NodeList.Nodes[0])
+			assert.NotEqual(t, "changed", v.Read.Message.Data)
+		}
+	}
+}
+
+func TestDeepCopyOfNestedEnvelope(t *testing.T) {
+	v := &OmiEnvelope{Response: &Response{Results: []RequestResult{
+		RequestResult{OmiEnvelope: &OmiEnvelope{Cancel: &CancelRequest{RequestIds: []Id{Id{Text: "REQ1"}}}}},
+	}}}
+	c := v.DeepCopy()
+	c.Response.Results[0].OmiEnvelope.Cancel.RequestIds[0].Text = "changed"
+	assert.Equal(t, "REQ1", v.Response.Results[0].OmiEnvelope.Cancel.RequestIds[0].Text)
+}
@@ -0,0 +1,89 @@
+package mi
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrNoNodeList = errors.New("mi: request has no nodeList to forward to")
+
+// Forward sends the request in envelope to every node in its nodeList and
+// merges the replies into a single response. Each result is tagged with the
+// node it came from and the request's targetType. Nodes that cannot be
+// reached are reported as results with returnCode 502.
+func Forward(sender Sender, envelope OmiEnvelope) (*Response, error) {
+	nodeList, targetType := envelope.routing()
+	if nodeList == nil || len(nodeList.Nodes) == 0 {
+		return nil, ErrNoNodeList
+	}
+
+	replies := make([][]RequestResult, len(nodeList.Nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range nodeList.Nodes {
+		wg.Add(1)
+		go func(i int, node string) {
+			defer wg.Done()
+			replies[i] = forwardTo(sender, node, envelope.withoutNodeList())
+		}(i, node)
+	}
+	wg.Wait()
+
+	response := &Response{}
+	for i, node := range nodeList.Nodes {
+		for _, result := range replies[i] {
+			result.NodeList = &NodeList{Type: nodeList.Type, Nodes: []string{node}}
+			if result.TargetType == "" {
+				result.TargetType = targetType
+			}
+			response.Results = append(response.Results, result)
+		}
+	}
+
+	return response, nil
+}
+
+func forwardTo(sender Sender, node string, envelope OmiEnvelope) []RequestResult {
+	reply, err := sender.Send(node, envelope)
+	if err != nil {
+		return []RequestResult{errorResult("502", err.Error())}
+	}
+	if reply == nil || reply.Response == nil || len(reply.Response.Results) == 0 {
+		return []RequestResult{errorResult("502", "node sent no response")}
+	}
+	return reply.Response.Results
+}
+
+func errorResult(code, description string) RequestResult {
+	return RequestResult{Return: &Return{ReturnCode: code, Description: description}}
+}
+
+func (e OmiEnvelope) routing() (*NodeList, string) {
+	switch {
+	case e.Read != nil:
+		return e.Read.NodeList, e.Read.TargetType
+	case e.Write != nil:
+		return e.Write.NodeList, e.Write.TargetType
+	case e.Cancel != nil:
+		return e.Cancel.NodeList, ""
+	}
+	return nil, ""
+}
+
+func (e OmiEnvelope) withoutNodeList() OmiEnvelope {
+	switch {
+	case e.Read != nil:
+		read := *e.Read
+		read.NodeList = nil
+		e.Read = &read
+	case e.Write != nil:
+		write := *e.Write
+		write.NodeList = nil
+		e.Write = &write
+	case e.Cancel != nil:
+		cancel := *e.Cancel
+		cancel.NodeList = nil
+		e.Cancel = &cancel
+	}
+	return e
+}
@@ -0,0 +1,120 @@
+package mi
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+type fakeSender struct {
+	mu       sync.Mutex
+	received map[string]OmiEnvelope
+	replies  map[string]*OmiEnvelope
+}
+
+func (s *fakeSender) Send(node string, envelope OmiEnvelope) (*OmiEnvelope, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received == nil {
+		s.received = map[string]OmiEnvelope{}
+	}
+	s.received[node] = envelope
+	reply, ok := s.replies[node]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return reply, nil
+}
+
+func okReply(data string) *OmiEnvelope {
+	return &OmiEnvelope{
+		Version: "1.0",
+		Response: &Response{
+			Results: []RequestResult{
+				RequestResult{
+					Return:  &Return{ReturnCode: "200"},
+					Message: &Message{Data: data},
+				},
+			},
+		},
+	}
+}
+
+func TestForwardReadRequestWithNodes(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/read_request_with_nodes.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			v.Read.TargetType = "device"
+			sender := &fakeSender{replies: map[string]*OmiEnvelope{
+				"http://192.168.0.1/": okReply("first"),
+				"http://192.168.0.2/": okReply("second"),
+			}}
+			response, err := Forward(sender, *v)
+			if assert.Nil(t, err) && assert.Len(t, response.Results, 2) {
+				assert.Equal(t, "first", response.Results[0].Message.Data)
+				assert.Equal(t, "URL", response.Results[0].NodeList.Type)
+				assert.Equal(t, []string{"http://192.168.0.1/"}, response.Results[0].NodeList.Nodes)
+				assert.Equal(t, "device", response.Results[0].TargetType)
+				assert.Equal(t, "second", response.Results[1].Message.Data)
+				assert.Equal(t, []string{"http://192.168.0.2/"}, response.Results[1].NodeList.Nodes)
+			}
+			for _, forwarded := range sender.received {
+				assert.Nil(t, forwarded.Read.NodeList)
+			}
+			assert.NotNil(t, v.Read.NodeList)
+		}
+	}
+}
+
+func TestForwardWithUnreachableNode(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/cancel_request_with_nodes.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			sender := &fakeSender{replies: map[string]*OmiEnvelope{
+				"http://192.168.0.2/": okReply(""),
+			}}
+			response, err := Forward(sender, *v)
+			if assert.Nil(t, err) && assert.Len(t, response.Results, 2) {
+				assert.Equal(t, "502", response.Results[0].Return.ReturnCode)
+				assert.Equal(t, "connection refused", response.Results[0].Return.Description)
+				assert.Equal(t, []string{"http://192.168.0.1/"}, response.Results[0].NodeList.Nodes)
+				assert.Equal(t, "200", response.Results[1].Return.ReturnCode)
+			}
+		}
+	}
+}
+
+func TestForwardWithNilReply(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/cancel_request_with_nodes.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			sender := &fakeSender{replies: map[string]*OmiEnvelope{
+				"http://192.168.0.1/": nil,
+				"http://192.168.0.2/": okReply(""),
+			}}
+			response, err := Forward(sender, *v)
+			if assert.Nil(t, err) && assert.Len(t, response.Results, 2) {
+				assert.Equal(t, "502", response.Results[0].Return.ReturnCode)
+				assert.Equal(t, "node sent no response", response.Results[0].Return.Description)
+				assert.Equal(t, "200", response.Results[1].Return.ReturnCode)
+			}
+		}
+	}
+}
+
+func TestForwardWithoutNodeList(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/read_request.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) {
+			response, err := Forward(&fakeSender{}, *v)
+			assert.Equal(t, ErrNoNodeList, err)
+			assert.Nil(t, response)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package mi
+
+import (
+	"encoding/xml"
+	"github.com/qlm-iot/qlm/internal/xmlscan"
+	"strconv"
+)
+
+var (
+	ErrTooLarge    = xmlscan.ErrTooLarge
+	ErrTooDeep     = xmlscan.ErrTooDeep
+	ErrAttrTooLong = xmlscan.ErrAttrTooLong
+)
+
+// LimitError is returned for an envelope that exceeds its Limits. It wraps
+// one of the Err values above or ErrEnvelopeDepth.
+type LimitError = xmlscan.LimitError
+
+// Limits bound the envelopes a Decoder accepts, payloads included. Zero
+// means no limit. Payloads are checked again by their codec when decoded.
+type Limits struct {
+	MaxBytes         int64
+	MaxDepth         int
+	MaxAttrLen       int
+	MaxEnvelopeDepth int
+}
+
+// DefaultLimits are used by Unmarshal.
+var DefaultLimits = Limits{
+	MaxBytes:         16 << 20,
+	MaxDepth:         256,
+	MaxAttrLen:       64 << 10,
+	MaxEnvelopeDepth: MaxEnvelopeDepth,
+}
+
+// scan checks data against l and checks the attributes that encoding/xml
+// would fail to decode, so that errors carry their position.
+func (l Limits) scan(data []byte) error {
+	envelopes := 0
+	payload := 0
+	return xmlscan.Scan(data, xmlscan.Limits{
+		MaxBytes:   l.MaxBytes,
+		MaxDepth:   l.MaxDepth,
+		MaxAttrLen: l.MaxAttrLen,
+	}, func(s *xmlscan.Scanner, token xml.Token) error {
+		switch t := token.(type) {
+		case xml.StartElement:
+			if payload > 0 || t.Name.Local == "msg" {
+				payload++
+				return nil
+			}
+			switch t.Name.Local {
+			case "omiEnvelope":
+				envelopes++
+				if l.MaxEnvelopeDepth > 0 && envelopes > l.MaxEnvelopeDepth {
+					return s.Exceeded(ErrEnvelopeDepth, l.MaxEnvelopeDepth)
+				}
+				return s.CheckFloat(t, "ttl", 64)
+			case "read":
+				for _, attr := range []string{"oldest", "newest", "maxlevels", "offset", "limit"} {
+					if err := s.CheckInt(t, attr, strconv.IntSize); err != nil {
+						return err
+					}
+				}
+				return s.CheckFloat(t, "interval", 64)
+			}
+		case xml.EndElement:
+			if payload > 0 {
+				payload--
+			} else if t.Name.Local == "omiEnvelope" {
+				envelopes--
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package mi
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecoderLimits(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/response_with_request.xml")
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = Decoder{}.Unmarshal(data)
+	assert.Nil(t, err)
+
+	for _, c := range []struct {
+		limits Limits
+		err    error
+	}{
+		{Limits{MaxBytes: 100}, ErrTooLarge},
+		{Limits{MaxDepth: 6}, ErrTooDeep},
+		{Limits{MaxAttrLen: 10}, ErrAttrTooLong},
+		{Limits{MaxEnvelopeDepth: 1}, ErrEnvelopeDepth},
+	} {
+		_, err := Decoder{Limits: c.limits}.Unmarshal(data)
+		assert.True(t, errors.Is(err, c.err), "%v: %v", c.limits, err)
+		var limit *LimitError
+		assert.True(t, errors.As(err, &limit))
+	}
+}
+
+func TestUnmarshalRejectsHostileEnvelopes(t *testing.T) {
+	deep := strings.Repeat("<msg>", 1000) + strings.Repeat("</msg>", 1000)
+	_, err := Unmarshal([]byte("<omiEnvelope><read>" + deep + "</read></omiEnvelope>"))
+	assert.True(t, errors.Is(err, ErrTooDeep))
+
+	long := strings.Repeat("x", DefaultLimits.MaxAttrLen+1)
+	_, err = Unmarshal([]byte(`<omiEnvelope version="` + long + `"></omiEnvelope>`))
+	assert.True(t, errors.Is(err, ErrAttrTooLong))
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	files, _ := filepath.Glob("examples/*.xml")
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			f.Add(data)
+		}
+	}
+	limits := Limits{MaxBytes: 8192, MaxDepth: 32, MaxAttrLen: 64, MaxEnvelopeDepth: 3}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		envelope, err := Decoder{Limits: limits}.Unmarshal(data)
+		if err != nil {
+			return
+		}
+		if d := envelope.depth(limits.MaxEnvelopeDepth + 1); d > limits.MaxEnvelopeDepth {
+			t.Fatalf("envelopes nested %d deep", d)
+		}
+		if _, err := Marshal(*envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.Response != nil {
+			for _, result := range envelope.Response.Results {
+				result.Payload()
+			}
+		}
+	})
+}
@@ -3,9 +3,29 @@ package mi
 import "encoding/xml"
 
 func Marshal(envelope OmiEnvelope) ([]byte, error) {
+	if envelope.depth(MaxEnvelopeDepth+1) > MaxEnvelopeDepth {
+		return nil, ErrEnvelopeDepth
+	}
 	root := struct {
 		OmiEnvelope
 		XMLName struct{} `xml:"omiEnvelope"`
 	}{OmiEnvelope: envelope}
 	return xml.MarshalIndent(root, "", "    ")
 }
+
+// depth returns the nesting depth of e, counting no further than limit so
+// that envelopes that contain themselves are caught.
+func (e *OmiEnvelope) depth(limit int) int {
+	max := 1
+	if e.Response == nil || limit <= 1 {
+		return max
+	}
+	for _, result := range e.Response.Results {
+		if result.OmiEnvelope != nil {
+			if d := 1 + result.OmiEnvelope.depth(limit-1); d > max {
+				max = d
+			}
+		}
+	}
+	return max
+}
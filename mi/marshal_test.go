@@ -577,3 +577,49 @@ func TestMarshalWriteRequest(t *testing.T) {
 	}
 	assertXML(t, envelope, expected)
 }
+
+func TestMarshalResponseWithRequest(t *testing.T) {
+	expected := `<omiEnvelope version="1.0" ttl="10">
+    <response>
+        <result>
+            <return returnCode="200"></return>
+            <omiEnvelope version="1.0" ttl="10">
+                <read></read>
+            </omiEnvelope>
+        </result>
+    </response>
+</omiEnvelope>`
+	envelope := OmiEnvelope{
+		Version: "1.0",
+		Ttl:     10,
+		Response: &Response{
+			Results: []RequestResult{
+				RequestResult{
+					Return:      &Return{ReturnCode: "200"},
+					OmiEnvelope: &OmiEnvelope{Version: "1.0", Ttl: 10, Read: &ReadRequest{}},
+				},
+			},
+		},
+	}
+	assertXML(t, envelope, expected)
+}
+
+func TestMarshalEnvelopeDepthLimit(t *testing.T) {
+	envelope := &OmiEnvelope{Version: "1.0"}
+	for i := 1; i < MaxEnvelopeDepth; i++ {
+		envelope = &OmiEnvelope{Version: "1.0", Response: &Response{
+			Results: []RequestResult{RequestResult{OmiEnvelope: envelope}},
+		}}
+	}
+	_, err := Marshal(*envelope)
+	assert.Nil(t, err)
+
+	deeper := OmiEnvelope{Response: &Response{Results: []RequestResult{RequestResult{OmiEnvelope: envelope}}}}
+	_, err = Marshal(deeper)
+	assert.Equal(t, ErrEnvelopeDepth, err)
+
+	cyclic := &OmiEnvelope{Response: &Response{Results: make([]RequestResult, 1)}}
+	cyclic.Response.Results[0].OmiEnvelope = cyclic
+	_, err = Marshal(*cyclic)
+	assert.Equal(t, ErrEnvelopeDepth, err)
+}
@@ -1,5 +1,7 @@
 package mi
 
+//go:generate go run ../internal/deepcopygen -o deepcopy_generated.go structs.go
+
 type OmiEnvelope struct {
 	Version  string         `xml:"version,attr"`
 	Ttl      float64        `xml:"ttl,attr"`
@@ -14,13 +16,16 @@ type Response struct {
 }
 
 type RequestResult struct {
-	Return      *Return   `xml:"return"`
-	RequestId   *Id       `xml:"requestId"`
-	Message     *Message  `xml:"msg"`
-	NodeList    *NodeList `xml:"nodeList"`
-	OmiEnvelope *OmiEnvelope
-	MsgFormat   string `xml:"msgformat,attr,omitempty"`
-	TargetType  string `xml:"targetType,attr,omitempty"`
+	Return      *Return      `xml:"return"`
+	RequestId   *Id          `xml:"requestId"`
+	Message     *Message     `xml:"msg"`
+	NodeList    *NodeList    `xml:"nodeList"`
+	OmiEnvelope *OmiEnvelope `xml:"omiEnvelope"`
+	MsgFormat   string       `xml:"msgformat,attr,omitempty"`
+	TargetType  string       `xml:"targetType,attr,omitempty"`
+	// NextOffset is the offset of the next page of a discovery read that
+	// was cut short by its limit.
+	NextOffset int `xml:"nextOffset,attr,omitempty"`
 }
 
 type Return struct {
@@ -55,6 +60,11 @@ type ReadRequest struct {
 	Newest     int       `xml:"newest,attr,omitempty"`
 	Begin      string    `xml:"begin,attr,omitempty"`
 	End        string    `xml:"end,attr,omitempty"`
+	// MaxLevels, Offset and Limit bound the trees returned for discovery
+	// reads; see df.DiscoveryOptions.
+	MaxLevels int `xml:"maxlevels,attr,omitempty"`
+	Offset    int `xml:"offset,attr,omitempty"`
+	Limit     int `xml:"limit,attr,omitempty"`
 }
 
 type WriteRequest struct {
@@ -1,13 +1,42 @@
 package mi
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/qlm-iot/qlm/internal/xmlscan"
+)
+
+// MaxEnvelopeDepth limits how deeply omiEnvelopes may be nested inside the
+// results of a response, counting the outermost envelope.
+const MaxEnvelopeDepth = 8
+
+var ErrEnvelopeDepth = errors.New("omiEnvelopes nested too deeply")
+
+// DecodeError is returned by Unmarshal for an envelope it cannot decode.
+// Path names the O-MI elements leading to the failure, e.g.
+// "omiEnvelope/read" with Attr "interval" for a non-numeric interval.
+type DecodeError = xmlscan.DecodeError
+
+// Decoder unmarshals O-MI envelopes that are within its Limits.
+type Decoder struct {
+	Limits Limits
+}
+
+func (d Decoder) Unmarshal(data []byte) (*OmiEnvelope, error) {
+	if err := d.Limits.scan(data); err != nil {
+		return nil, err
+	}
 
-func Unmarshal(data []byte) (*OmiEnvelope, error) {
 	v := &OmiEnvelope{}
 
 	if err := xml.Unmarshal(data, v); err != nil {
-		return nil, err
+		return nil, xmlscan.Wrap(err)
 	}
 
 	return v, nil
 }
+
+// Unmarshal decodes an O-MI envelope within DefaultLimits.
+func Unmarshal(data []byte) (*OmiEnvelope, error) {
+	return Decoder{Limits: DefaultLimits}.Unmarshal(data)
+}
@@ -1,6 +1,7 @@
 package mi
 
 import (
+	"errors"
 	"github.com/qlm-iot/qlm/df"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
@@ -14,6 +15,41 @@ func TestUnmarshalWithInvalidXML(t *testing.T) {
 	assert.Nil(t, v)
 }
 
+func TestUnmarshalErrorPosition(t *testing.T) {
+	for _, c := range []struct {
+		data, path, attr, err string
+	}{
+		{`<omiEnvelope ttl="ten"></omiEnvelope>`, "omiEnvelope", "ttl", `1:1: omiEnvelope@ttl: invalid number "ten": invalid syntax`},
+		{"<omiEnvelope ttl=\"10\">\n  <read interval=\"often\"></read></omiEnvelope>", "omiEnvelope/read", "interval", `2:3: omiEnvelope/read@interval: invalid number "often": invalid syntax`},
+		{`<omiEnvelope><read newest="1.5"></read></omiEnvelope>`, "omiEnvelope/read", "newest", `1:14: omiEnvelope/read@newest: invalid integer "1.5": invalid syntax`},
+		{`<omiEnvelope><read maxlevels="2" limit="all"></read></omiEnvelope>`, "omiEnvelope/read", "limit", `1:14: omiEnvelope/read@limit: invalid integer "all": invalid syntax`},
+		{`<omiEnvelope><write><msg><read interval="x"/></write></omiEnvelope>`, "omiEnvelope/write/msg", "", ""},
+	} {
+		_, err := Unmarshal([]byte(c.data))
+		var decodeErr *DecodeError
+		if assert.True(t, errors.As(err, &decodeErr), c.data) {
+			assert.Equal(t, c.path, decodeErr.Path)
+			assert.Equal(t, c.attr, decodeErr.Attr)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+			}
+		}
+	}
+
+	// Attributes inside payloads are left to the payload's codec.
+	_, err := Unmarshal([]byte(`<omiEnvelope><read><msg><read interval="x"/></msg></read></omiEnvelope>`))
+	assert.Nil(t, err)
+}
+
+func TestUnmarshalReadRequestWithPaging(t *testing.T) {
+	v, err := Unmarshal([]byte(`<omiEnvelope version="1.0" ttl="10"><read msgformat="odf" maxlevels="2" offset="20" limit="10"><msg><Objects/></msg></read></omiEnvelope>`))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, v.Read.MaxLevels)
+		assert.Equal(t, 20, v.Read.Offset)
+		assert.Equal(t, 10, v.Read.Limit)
+	}
+}
+
 func TestUnmarshalCancelRequest(t *testing.T) {
 	data, err := ioutil.ReadFile("examples/cancel_request.xml")
 	if assert.Nil(t, err) {
@@ -321,3 +357,55 @@ func TestIntegrationBetweenMiAndDf(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshalResponseWithRequest(t *testing.T) {
+	data, err := ioutil.ReadFile("examples/response_with_request.xml")
+	if assert.Nil(t, err) {
+		v, err := Unmarshal(data)
+		if assert.Nil(t, err) && assert.Len(t, v.Response.Results, 2) {
+			request := v.Response.Results[0].OmiEnvelope
+			if assert.NotNil(t, request) && assert.NotNil(t, request.Read) {
+				assert.Equal(t, "1.0", request.Version)
+				assert.Equal(t, "odf", request.Read.MsgFormat)
+				objects, err := df.Unmarshal([]byte(request.Read.Message.Data))
+				if assert.Nil(t, err) {
+					assert.NotNil(t, objects.InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption")))
+				}
+			}
+
+			forwarded := v.Response.Results[1].OmiEnvelope
+			if assert.NotNil(t, forwarded) && assert.NotNil(t, forwarded.Response) {
+				assert.Equal(t, "404", forwarded.Response.Results[0].Return.ReturnCode)
+			}
+
+			// The round trip keeps the embedded envelopes.
+			data, err := Marshal(*v)
+			if assert.Nil(t, err) {
+				again, err := Unmarshal(data)
+				if assert.Nil(t, err) {
+					assert.Equal(t, v, again)
+				}
+			}
+		}
+	}
+}
+
+func TestUnmarshalEnvelopeDepthLimit(t *testing.T) {
+	nested := func(depth int) string {
+		data := `<omiEnvelope version="1.0">`
+		for i := 1; i < depth; i++ {
+			data += `<response><result><omiEnvelope version="1.0">`
+		}
+		for i := 1; i < depth; i++ {
+			data += `</omiEnvelope></result></response>`
+		}
+		return data + `</omiEnvelope>`
+	}
+
+	v, err := Unmarshal([]byte(nested(MaxEnvelopeDepth)))
+	if assert.Nil(t, err) {
+		assert.Equal(t, MaxEnvelopeDepth, v.depth(MaxEnvelopeDepth+1))
+	}
+	_, err = Unmarshal([]byte(nested(MaxEnvelopeDepth + 1)))
+	assert.True(t, errors.Is(err, ErrEnvelopeDepth))
+}
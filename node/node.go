@@ -0,0 +1,166 @@
+package node
+
+import (
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Node answers O-MI requests from a Store. Subscriptions are poll based:
+// values written to the store are buffered for every subscription covering
+// them until the client reads them with the subscription's requestId.
+// Interval and callback subscriptions are answered with returnCode 501.
+type Node struct {
+	Store         *Store
+	Subscriptions *Subscriptions
+	// Authorize, when set, is consulted for every InfoItem of a write request.
+	Authorize AuthorizeFunc
+	// Limits bound the envelopes accepted by ServeHTTP.
+	Limits mi.Limits
+}
+
+func New(objects df.Objects) *Node {
+	n := &Node{
+		Store:         NewStore(objects),
+		Subscriptions: NewSubscriptions(DefaultMaxBufferBytes),
+		Limits:        mi.DefaultLimits,
+	}
+	n.Store.OnWrite = func(path df.Path, values []df.Value) {
+		n.Subscriptions.Publish(path, values...)
+	}
+	return n
+}
+
+// Publish stores values of the InfoItem at path as the node's own
+// measurements, bypassing Authorize.
+func (n *Node) Publish(path df.Path, values ...df.Value) error {
+	objects := df.Objects{}
+	objects.Add(path, values...)
+	_, err := n.Store.Write(objects, nil)
+	return err
+}
+
+// Handle answers a request envelope with a response envelope.
+func (n *Node) Handle(envelope mi.OmiEnvelope) mi.OmiEnvelope {
+	var response *mi.Response
+	switch {
+	case envelope.Read != nil && len(envelope.Read.RequestIds) > 0:
+		response = n.Subscriptions.Read(envelope.Read)
+	case envelope.Read != nil && envelope.Read.Interval != 0:
+		if reason := unsupported(envelope.Read); reason != "" {
+			response = &mi.Response{Results: []mi.RequestResult{errorResult("501", reason)}}
+		} else {
+			response = n.subscribe(envelope.Read, envelope.Ttl)
+		}
+	case envelope.Read != nil:
+		response = n.Store.HandleRead(envelope.Read, ReadOptionsFor(envelope.Read))
+	case envelope.Write != nil:
+		response = n.Store.HandleWrite(envelope.Write, n.Authorize)
+	case envelope.Cancel != nil:
+		response = n.cancel(envelope.Cancel)
+	default:
+		response = &mi.Response{Results: []mi.RequestResult{errorResult("400", "envelope has no request")}}
+	}
+	return mi.OmiEnvelope{Version: "1.0", Response: response}
+}
+
+// unsupported explains why the subscription read cannot be honoured: the
+// node only keeps event based subscriptions that are polled by requestId.
+func unsupported(read *mi.ReadRequest) string {
+	switch {
+	case read.Interval > 0:
+		return "interval subscriptions are not supported, use interval -1"
+	case read.Callback != "":
+		return "callbacks are not supported, poll the subscription by requestId"
+	}
+	return ""
+}
+
+func (n *Node) subscribe(read *mi.ReadRequest, ttl float64) *mi.Response {
+	query, err := decodePayload(read.MsgFormat, read.Message)
+	if err != nil {
+		return &mi.Response{Results: []mi.RequestResult{errorResult("400", err.Error())}}
+	}
+
+	paths := []df.Path{}
+	var collect func(parent df.Path, objects []df.Object)
+	collect = func(parent df.Path, objects []df.Object) {
+		for i := range objects {
+			path := parent.Child(objects[i].ID())
+			if len(objects[i].InfoItems) == 0 && len(objects[i].Objects) == 0 {
+				paths = append(paths, path)
+			}
+			for _, item := range objects[i].InfoItems {
+				paths = append(paths, path.Child(item.Name))
+			}
+			collect(path, objects[i].Objects)
+		}
+	}
+	collect(df.Path{}, query.Objects)
+	if len(query.Objects) == 0 {
+		paths = append(paths, df.Path{})
+	}
+
+	id := n.Subscriptions.Subscribe(paths, time.Duration(ttl*float64(time.Second)))
+	return &mi.Response{Results: []mi.RequestResult{mi.RequestResult{
+		Return:    &mi.Return{ReturnCode: "200"},
+		RequestId: &mi.Id{Text: id},
+	}}}
+}
+
+func (n *Node) cancel(cancel *mi.CancelRequest) *mi.Response {
+	response := &mi.Response{}
+	for _, id := range cancel.RequestIds {
+		requestId := id
+		result := mi.RequestResult{Return: &mi.Return{ReturnCode: "200"}, RequestId: &requestId}
+		if err := n.Subscriptions.Cancel(id.Text); err != nil {
+			result.Return = &mi.Return{ReturnCode: "404", Description: "Not Found"}
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response
+}
+
+// ServeHTTP accepts envelopes posted either as the request body or as the
+// msg form value.
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "O-MI requests must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var data []byte
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		data = []byte(r.FormValue("msg"))
+	} else {
+		var body io.Reader = r.Body
+		if n.Limits.MaxBytes > 0 {
+			body = io.LimitReader(body, n.Limits.MaxBytes+1)
+		}
+		read, err := ioutil.ReadAll(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data = read
+	}
+
+	reply := mi.OmiEnvelope{Version: "1.0", Response: &mi.Response{Results: []mi.RequestResult{}}}
+	if envelope, err := (mi.Decoder{Limits: n.Limits}).Unmarshal(data); err != nil {
+		reply.Response.Results = append(reply.Response.Results, errorResult("400", err.Error()))
+	} else {
+		reply = n.Handle(*envelope)
+	}
+
+	out, err := mi.Marshal(reply)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.Write(out)
+}
@@ -0,0 +1,232 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func loadNode(t *testing.T) *Node {
+	data, err := ioutil.ReadFile("../df/examples/measurement_values_for_refrigerator_power_consumption.xml")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	v, err := df.Unmarshal(data)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return New(*v)
+}
+
+func TestNodeSubscribeAndPoll(t *testing.T) {
+	n := loadNode(t)
+	path := df.ParsePath("SmartFridge22334411/Consumed Electrical Power Measure")
+
+	reply := n.Handle(mi.OmiEnvelope{Version: "1.0", Ttl: -1, Read: &mi.ReadRequest{
+		MsgFormat: "odf",
+		Interval:  -1,
+		Message:   &mi.Message{Data: `<Objects><Object><id>SmartFridge22334411</id></Object></Objects>`},
+	}})
+	if !assert.Len(t, reply.Response.Results, 1) {
+		return
+	}
+	id := reply.Response.Results[0].RequestId.Text
+
+	assert.Nil(t, n.Publish(path, df.Value{Text: "16.1"}))
+
+	reply = n.Handle(mi.OmiEnvelope{Version: "1.0", Read: &mi.ReadRequest{RequestIds: []mi.Id{mi.Id{Text: id}}}})
+	if assert.Len(t, reply.Response.Results, 1) {
+		objects, err := df.Unmarshal([]byte(reply.Response.Results[0].Message.Data))
+		if assert.Nil(t, err) {
+			item := objects.InfoItem(path)
+			if assert.NotNil(t, item) && assert.Len(t, item.Values, 1) {
+				assert.Equal(t, "16.1", item.Values[0].Text)
+			}
+		}
+	}
+
+	reply = n.Handle(mi.OmiEnvelope{Version: "1.0", Cancel: &mi.CancelRequest{RequestIds: []mi.Id{mi.Id{Text: id}, mi.Id{Text: id}}}})
+	if assert.Len(t, reply.Response.Results, 2) {
+		assert.Equal(t, "200", reply.Response.Results[0].Return.ReturnCode)
+		assert.Equal(t, "404", reply.Response.Results[1].Return.ReturnCode)
+	}
+}
+
+func TestNodeBoundsAbandonedSubscriptions(t *testing.T) {
+	n := loadNode(t)
+	assert.Equal(t, DefaultMaxBufferBytes, n.Subscriptions.MaxBytes)
+	n.Subscriptions.MaxBytes = 1024
+	path := df.ParsePath("SmartFridge22334411/Consumed Electrical Power Measure")
+	id := n.Subscriptions.Subscribe([]df.Path{path}, -1)
+	for i := 0; i < 1000; i++ {
+		assert.Nil(t, n.Publish(path, df.Value{Text: fmt.Sprint(i)}))
+	}
+	sub := n.Subscriptions.subs[id]
+	if assert.NotNil(t, sub) {
+		assert.True(t, sub.size <= 1024)
+		assert.Equal(t, "999", sub.values[len(sub.values)-1].value.Text)
+	}
+}
+
+func TestNodeOverHTTP(t *testing.T) {
+	server := httptest.NewServer(loadNode(t))
+	defer server.Close()
+	client := &mi.Client{}
+
+	reply, err := client.Send(server.URL, mi.OmiEnvelope{Version: "1.0", Write: &mi.WriteRequest{
+		MsgFormat: "odf",
+		Message:   &mi.Message{Data: `<Objects><Object><id>SmartFridge22334411</id><InfoItem name="FridgeTemperatureSetpoint"><value>3.5</value></InfoItem></Object></Objects>`},
+	}})
+	if assert.Nil(t, err) && assert.Len(t, reply.Response.Results, 1) {
+		assert.Equal(t, "200", reply.Response.Results[0].Return.ReturnCode)
+	}
+
+	reply, err = client.Send(server.URL, mi.OmiEnvelope{Version: "1.0", Read: &mi.ReadRequest{
+		MsgFormat: "odf",
+		Message:   &mi.Message{Data: `<Objects><Object><id>SmartFridge22334411</id><InfoItem name="FridgeTemperatureSetpoint"/></Object></Objects>`},
+	}})
+	if assert.Nil(t, err) && assert.Len(t, reply.Response.Results, 1) {
+		objects, err := df.Unmarshal([]byte(reply.Response.Results[0].Message.Data))
+		if assert.Nil(t, err) {
+			assert.Equal(t, "3.5", objects.Objects[0].InfoItems[0].Values[0].Text)
+		}
+	}
+}
+
+func TestNodeServesReadRequestExample(t *testing.T) {
+	data, err := ioutil.ReadFile("../mi/examples/read_request.xml")
+	if !assert.Nil(t, err) {
+		return
+	}
+	objects := df.Objects{}
+	objects.Add(df.ParsePath("SmartFridge22334411/PowerConsumption"), df.Value{Text: "120"})
+	server := httptest.NewServer(New(objects))
+	defer server.Close()
+
+	subscribe := bytes.Replace(data, []byte(`interval="3.5"`), []byte(`interval="-1"`), 1)
+	resp, err := http.Post(server.URL, "text/xml", bytes.NewReader(subscribe))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if !assert.Nil(t, err) {
+		return
+	}
+	reply, err := mi.Unmarshal(body)
+	if assert.Nil(t, err) && assert.Len(t, reply.Response.Results, 1) {
+		assert.Equal(t, "200", reply.Response.Results[0].Return.ReturnCode)
+		assert.NotNil(t, reply.Response.Results[0].RequestId)
+	}
+
+	request, err := mi.Unmarshal(data)
+	if !assert.Nil(t, err) {
+		return
+	}
+	request.Read.Interval = 0
+	result := New(objects).Handle(*request)
+	if assert.Len(t, result.Response.Results, 1) {
+		assert.Equal(t, "200", result.Response.Results[0].Return.ReturnCode)
+		read, err := df.Unmarshal([]byte(result.Response.Results[0].Message.Data))
+		if assert.Nil(t, err) {
+			item := read.InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption"))
+			if assert.NotNil(t, item) && assert.Len(t, item.Values, 1) {
+				assert.Equal(t, "120", item.Values[0].Text)
+			}
+		}
+	}
+}
+
+func TestNodeRejectsUnsupportedSubscriptions(t *testing.T) {
+	n := loadNode(t)
+	message := &mi.Message{Data: `<Objects><Object><id>SmartFridge22334411</id></Object></Objects>`}
+	for _, c := range []struct {
+		read *mi.ReadRequest
+		code string
+	}{
+		{&mi.ReadRequest{MsgFormat: "odf", Interval: 5, Message: message}, "501"},
+		{&mi.ReadRequest{MsgFormat: "odf", Interval: -1, Callback: "http://localhost/", Message: message}, "501"},
+		{&mi.ReadRequest{MsgFormat: "odf", Interval: -1, Message: message}, "200"},
+		{&mi.ReadRequest{MsgFormat: "odf", Message: message}, "200"},
+	} {
+		reply := n.Handle(mi.OmiEnvelope{Version: "1.0", Ttl: 10, Read: c.read})
+		if assert.Len(t, reply.Response.Results, 1) {
+			assert.Equal(t, c.code, reply.Response.Results[0].Return.ReturnCode)
+		}
+	}
+}
+
+func TestNodeRejectsEmptyEnvelope(t *testing.T) {
+	reply := loadNode(t).Handle(mi.OmiEnvelope{Version: "1.0"})
+	if assert.Len(t, reply.Response.Results, 1) {
+		assert.Equal(t, "400", reply.Response.Results[0].Return.ReturnCode)
+	}
+}
+
+func TestNodePagesThroughDiscovery(t *testing.T) {
+	objects := df.Objects{}
+	for i := 0; i < 25; i++ {
+		objects.Add(df.Path{fmt.Sprintf("Device%02d", i), "Sensor", "Temperature"}, df.Value{Text: "21"})
+	}
+	for i := 0; i < 25; i++ {
+		objects.AddObject(df.Path{"Building", fmt.Sprintf("Room%02d", i)})
+	}
+	server := httptest.NewServer(New(objects))
+	defer server.Close()
+	client := &mi.Client{}
+
+	discover := func(query string, offset int) ([]df.Object, int) {
+		reply, err := client.Send(server.URL, mi.OmiEnvelope{Version: "1.0", Read: &mi.ReadRequest{
+			MsgFormat: "odf",
+			MaxLevels: 2,
+			Offset:    offset,
+			Limit:     10,
+			Message:   &mi.Message{Data: query},
+		}})
+		if !assert.Nil(t, err) || !assert.Len(t, reply.Response.Results, 1) {
+			t.FailNow()
+		}
+		result := reply.Response.Results[0]
+		page, err := df.Unmarshal([]byte(result.Message.Data))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		return page.Objects, result.NextOffset
+	}
+
+	ids := []string{}
+	offsets := []int{}
+	for offset := 0; ; {
+		page, next := discover(`<Objects/>`, offset)
+		for _, object := range page {
+			ids = append(ids, object.ID())
+			if object.ID() == "Device00" && assert.Len(t, object.Objects, 1) {
+				assert.Equal(t, "Sensor", object.Objects[0].ID())
+				assert.Len(t, object.Objects[0].InfoItems, 0)
+			}
+		}
+		offsets = append(offsets, next)
+		if next == 0 {
+			break
+		}
+		offset = next
+	}
+	assert.Equal(t, []int{10, 20, 0}, offsets)
+	assert.Len(t, ids, 26)
+	assert.Equal(t, "Device00", ids[0])
+	assert.Equal(t, "Building", ids[25])
+
+	page, next := discover(`<Objects><Object><id>Building</id></Object></Objects>`, 20)
+	if assert.Len(t, page, 1) && assert.Len(t, page[0].Objects, 5) {
+		assert.Equal(t, "Room20", page[0].Objects[0].ID())
+	}
+	assert.Equal(t, 0, next)
+	_, next = discover(`<Objects><Object><id>Building</id></Object></Objects>`, 0)
+	assert.Equal(t, 10, next)
+}
@@ -0,0 +1,171 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"time"
+)
+
+var ErrNotFound = errors.New("node: not found")
+
+// DefaultDiscoveryDepth is the number of Object levels listed by discovery
+// reads of requests that do not ask for more.
+const DefaultDiscoveryDepth = 1
+
+type ReadOptions struct {
+	Projection df.Projection
+	Discovery  df.DiscoveryOptions
+	// Newest and Oldest limit the values returned per InfoItem to the last
+	// or first n stored ones. Zero means no limit.
+	Newest int
+	Oldest int
+}
+
+// ReadOptionsFor takes the options of a read from its oldest, newest,
+// maxlevels, offset and limit attributes.
+func ReadOptionsFor(read *mi.ReadRequest) ReadOptions {
+	depth := read.MaxLevels
+	if depth <= 0 {
+		depth = DefaultDiscoveryDepth
+	}
+	return ReadOptions{
+		Newest: read.Newest,
+		Oldest: read.Oldest,
+		Discovery: df.DiscoveryOptions{
+			Depth:  depth,
+			Offset: read.Offset,
+			Limit:  read.Limit,
+		},
+	}
+}
+
+// Read answers an O-DF query against the store. An empty InfoItem in query
+// asks for its values, one with an empty MetaData element for its metadata
+// and one with an empty description for its description. An empty Objects
+// element or an Object with only its id is a discovery request and is
+// answered with the structure below it. Objects are matched by any of their
+// ids that is valid now.
+func (s *Store) Read(query df.Objects, options ReadOptions) (df.Objects, error) {
+	result, _, err := s.read(query, options)
+	return result, err
+}
+
+// read also returns the offset of the next page when a discovery was cut
+// short by options.Discovery.Limit, and zero otherwise.
+func (s *Store) read(query df.Objects, options ReadOptions) (df.Objects, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := s.objects
+	result.Objects = nil
+	next := 0
+	if len(query.Objects) == 0 {
+		result = s.objects.Discover(options.Discovery)
+		next = nextOffset(len(s.objects.Objects), options.Discovery)
+	} else {
+		objects, err := readObjects(df.Path{}, s.objects.Objects, query.Objects, options, time.Now(), &next)
+		if err != nil {
+			return df.Objects{}, 0, err
+		}
+		result.Objects = objects
+	}
+	return result.Project(options.Projection), next, nil
+}
+
+func nextOffset(total int, options df.DiscoveryOptions) int {
+	if options.Limit > 0 && options.Offset+options.Limit < total {
+		return options.Offset + options.Limit
+	}
+	return 0
+}
+
+// HandleRead answers an O-MI read request carrying an O-DF query.
+func (s *Store) HandleRead(read *mi.ReadRequest, options ReadOptions) *mi.Response {
+	query, err := decodePayload(read.MsgFormat, read.Message)
+	if err != nil {
+		return &mi.Response{Results: []mi.RequestResult{errorResult("400", err.Error())}}
+	}
+
+	objects, next, err := s.read(*query, options)
+	if errors.Is(err, ErrNotFound) {
+		return &mi.Response{Results: []mi.RequestResult{errorResult("404", err.Error())}}
+	} else if err != nil {
+		return &mi.Response{Results: []mi.RequestResult{errorResult("400", err.Error())}}
+	}
+	result := odfResult(objects)
+	result.NextOffset = next
+	return &mi.Response{Results: []mi.RequestResult{result}}
+}
+
+// readObjects answers the query for stored, matching each Object by any of
+// its ids that is valid at now.
+func readObjects(parent df.Path, stored []df.Object, query []df.Object, options ReadOptions, now time.Time, next *int) ([]df.Object, error) {
+	result := []df.Object{}
+	for i := range query {
+		path := parent.Child(query[i].ID())
+		object := findObject(stored, query[i].ID(), now)
+		if object == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+
+		if len(query[i].InfoItems) == 0 && len(query[i].Objects) == 0 {
+			result = append(result, object.Discover(options.Discovery))
+			if n := nextOffset(len(object.Objects), options.Discovery); n > 0 {
+				*next = n
+			}
+			continue
+		}
+
+		found := df.Object{Type: object.Type, Udef: object.Udef, Id: object.Id, OtherIds: object.OtherIds}
+		for _, q := range query[i].InfoItems {
+			item := object.InfoItem(q.Name)
+			if item == nil {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, path.Child(q.Name))
+			}
+			found.InfoItems = append(found.InfoItems, readInfoItem(*item, q, options))
+		}
+		children, err := readObjects(path, object.Objects, query[i].Objects, options, now, next)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) > 0 {
+			found.Objects = children
+		}
+		result = append(result, found)
+	}
+	return result, nil
+}
+
+func readInfoItem(item df.InfoItem, query df.InfoItem, options ReadOptions) df.InfoItem {
+	switch {
+	case query.MetaData != nil:
+		metaData := item.MetaData
+		if metaData == nil {
+			metaData = &df.MetaData{}
+		}
+		return df.InfoItem{Name: item.Name, MetaData: metaData}
+	case query.Description != nil:
+		return df.InfoItem{Name: item.Name, Description: item.Description}
+	}
+
+	values := item.Values
+	if options.Newest > 0 && len(values) > options.Newest {
+		values = values[len(values)-options.Newest:]
+	}
+	if options.Oldest > 0 && len(values) > options.Oldest {
+		values = values[:options.Oldest]
+	}
+	item.Values = values
+	return item
+}
+
+func findObject(objects []df.Object, id string, now time.Time) *df.Object {
+	for i := range objects {
+		if objects[i].HasId(id, &now) {
+			return &objects[i]
+		}
+	}
+	return nil
+}
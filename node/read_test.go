@@ -0,0 +1,156 @@
+package node
+
+import (
+	"errors"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func loadStore(t *testing.T, name string) *Store {
+	data, err := ioutil.ReadFile("../df/examples/" + name)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	v, err := df.Unmarshal(data)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return NewStore(*v)
+}
+
+func readRequest(query string) *mi.ReadRequest {
+	return &mi.ReadRequest{MsgFormat: "odf", Message: &mi.Message{Data: query}}
+}
+
+func TestStoreReadValues(t *testing.T) {
+	store := loadStore(t, "measurement_values_for_refrigerator_power_consumption.xml")
+	read := readRequest(`<Objects><Object><id>SmartFridge22334411</id><InfoItem name="Consumed Electrical Power Measure"/></Object></Objects>`)
+	read.Newest = 2
+
+	response := store.HandleRead(read, ReadOptionsFor(read))
+	if assert.Len(t, response.Results, 1) {
+		assert.Equal(t, "200", response.Results[0].Return.ReturnCode)
+		objects, err := df.Unmarshal([]byte(response.Results[0].Message.Data))
+		if assert.Nil(t, err) {
+			item := objects.InfoItem(df.ParsePath("SmartFridge22334411/Consumed Electrical Power Measure"))
+			if assert.NotNil(t, item) && assert.Len(t, item.Values, 2) {
+				assert.Equal(t, "1.5", item.Values[0].Text)
+				assert.Equal(t, "15.3", item.Values[1].Text)
+			}
+		}
+	}
+}
+
+func TestStoreReadMetaData(t *testing.T) {
+	store := loadStore(t, "metadata_about_refrigerator_power_consumption.xml")
+	query := df.Objects{Objects: []df.Object{df.Object{
+		Id:        &df.QLMID{Text: "SmartFridge22334411"},
+		InfoItems: []df.InfoItem{df.InfoItem{Name: "PowerConsumption", MetaData: &df.MetaData{}}},
+	}}}
+
+	objects, err := store.Read(query, ReadOptions{})
+	if assert.Nil(t, err) {
+		item := objects.InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption"))
+		if assert.NotNil(t, item) && assert.NotNil(t, item.MetaData) {
+			assert.Len(t, item.MetaData.InfoItems, 6)
+			assert.Equal(t, "format", item.MetaData.InfoItems[0].Name)
+		}
+	}
+}
+
+func TestStoreReadDescription(t *testing.T) {
+	store := loadStore(t, "measurement_values_for_refrigerator_power_consumption.xml")
+	query := df.Objects{Objects: []df.Object{df.Object{
+		Id:        &df.QLMID{Text: "SmartFridge22334411"},
+		InfoItems: []df.InfoItem{df.InfoItem{Name: "Consumed Electrical Power Measure", Description: &df.Description{}}},
+	}}}
+
+	objects, err := store.Read(query, ReadOptions{})
+	if assert.Nil(t, err) {
+		item := objects.Objects[0].InfoItems[0]
+		assert.Len(t, item.Values, 0)
+		assert.Equal(t, "Power consumption values with timestamp.", item.Description.Text)
+	}
+}
+
+func TestStoreReadWithProjection(t *testing.T) {
+	store := loadStore(t, "measurement_values_for_refrigerator_power_consumption.xml")
+
+	objects, err := store.Read(df.Objects{}, ReadOptions{Projection: df.StructureOnly})
+	if assert.Nil(t, err) && assert.Len(t, objects.Objects, 1) {
+		item := objects.Objects[0].InfoItems[0]
+		assert.Equal(t, "Consumed Electrical Power Measure", item.Name)
+		assert.Len(t, item.Values, 0)
+		assert.Nil(t, item.Description)
+	}
+}
+
+func TestStoreReadNotFound(t *testing.T) {
+	store := loadStore(t, "measurement_values_for_refrigerator_power_consumption.xml")
+	read := readRequest(`<Objects><Object><id>SmartFridge22334411</id><InfoItem name="Missing"/></Object></Objects>`)
+
+	response := store.HandleRead(read, ReadOptions{})
+	if assert.Len(t, response.Results, 1) {
+		assert.Equal(t, "404", response.Results[0].Return.ReturnCode)
+	}
+}
+
+func TestStoreReadMatchesActiveIds(t *testing.T) {
+	store := NewStore(df.Objects{Objects: []df.Object{df.Object{
+		Id: &df.QLMID{Text: "Fridge-2016", EndDate: "2016-12-31T23:59:59Z"},
+		OtherIds: []df.QLMID{
+			df.QLMID{Text: "Fridge-2017", StartDate: "2017-01-01T00:00:00Z"},
+			df.QLMID{Text: "Fridge-2999", StartDate: "2999-01-01T00:00:00Z"},
+		},
+		InfoItems: []df.InfoItem{df.InfoItem{Name: "Power", Values: []df.Value{df.Value{Text: "120"}}}},
+	}}})
+	for id, found := range map[string]bool{"Fridge-2016": false, "Fridge-2017": true, "Fridge-2999": false} {
+		_, err := store.Read(df.Objects{Objects: []df.Object{df.Object{
+			Id:        &df.QLMID{Text: id},
+			InfoItems: []df.InfoItem{df.InfoItem{Name: "Power"}},
+		}}}, ReadOptions{})
+		if found {
+			assert.Nil(t, err, id)
+		} else {
+			assert.True(t, errors.Is(err, ErrNotFound), id)
+		}
+	}
+}
+
+func TestStoreReadDiscoversObjects(t *testing.T) {
+	store := loadStore(t, "object_object_infoitem_values.xml")
+	read := readRequest(`<Objects/>`)
+
+	response := store.HandleRead(read, ReadOptionsFor(read))
+	if assert.Len(t, response.Results, 1) {
+		objects, err := df.Unmarshal([]byte(response.Results[0].Message.Data))
+		if assert.Nil(t, err) && assert.Len(t, objects.Objects, 1) {
+			assert.Equal(t, "UniqueTargetID_1", objects.Objects[0].ID())
+			assert.Len(t, objects.Objects[0].InfoItems, 0)
+			assert.Len(t, objects.Objects[0].Objects, 0)
+		}
+	}
+}
+
+func TestStoreReadDiscoversObject(t *testing.T) {
+	store := loadStore(t, "object_object_infoitem_values.xml")
+	query := df.Objects{Objects: []df.Object{df.Object{
+		Id:      &df.QLMID{Text: "UniqueTargetID_1"},
+		Objects: []df.Object{df.Object{Id: &df.QLMID{Text: "SubTarget1"}}},
+	}}}
+
+	objects, err := store.Read(query, ReadOptions{Discovery: df.DiscoveryOptions{Depth: 1}})
+	if assert.Nil(t, err) {
+		object := objects.Object(df.ParsePath("UniqueTargetID_1/SubTarget1"))
+		if assert.NotNil(t, object) && assert.Len(t, object.InfoItems, 1) {
+			assert.Equal(t, "SubInfoItem1", object.InfoItems[0].Name)
+			if assert.Len(t, object.Objects, 1) {
+				assert.Equal(t, "SubSubTarget1", object.Objects[0].ID())
+				assert.Len(t, object.Objects[0].InfoItems, 0)
+			}
+		}
+	}
+}
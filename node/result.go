@@ -0,0 +1,22 @@
+package node
+
+import (
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+)
+
+func odfResult(objects df.Objects) mi.RequestResult {
+	message, err := mi.EncodePayload("odf", objects)
+	if err != nil {
+		return errorResult("500", err.Error())
+	}
+	return mi.RequestResult{
+		MsgFormat: "odf",
+		Return:    &mi.Return{ReturnCode: "200"},
+		Message:   message,
+	}
+}
+
+func errorResult(code, description string) mi.RequestResult {
+	return mi.RequestResult{Return: &mi.Return{ReturnCode: code, Description: description}}
+}
@@ -0,0 +1,217 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"sync"
+)
+
+var (
+	ErrForbidden = errors.New("node: write not allowed")
+	ErrNoValues  = errors.New("node: InfoItem has no values")
+)
+
+// AuthorizeFunc accepts or rejects the write of item to path. current is a
+// copy of the stored InfoItem, or nil when the write would create it. A rejection that
+// wraps ErrForbidden is reported with returnCode 403, any other with 400.
+type AuthorizeFunc func(path df.Path, current *df.InfoItem, item df.InfoItem) error
+
+// EnforceMetaData wraps authorize with the checks declared by the MetaData
+// of the stored InfoItem: writes to an InfoItem that is not writable are
+// forbidden and values must match its format. authorize may be nil.
+func EnforceMetaData(authorize AuthorizeFunc) AuthorizeFunc {
+	return func(path df.Path, current *df.InfoItem, item df.InfoItem) error {
+		if current != nil {
+			meta, err := current.Meta()
+			if err != nil {
+				return err
+			}
+			if !meta.CanWrite() {
+				return fmt.Errorf("%w: %s is not writable", ErrForbidden, path)
+			}
+			for _, value := range item.Values {
+				if err := meta.Check(value); err != nil {
+					return err
+				}
+			}
+		}
+		if authorize != nil {
+			return authorize(path, current, item)
+		}
+		return nil
+	}
+}
+
+// Rejection describes an InfoItem that was left out of a write.
+type Rejection struct {
+	Path df.Path
+	Err  error
+}
+
+// Store holds the O-DF tree served by a node.
+type Store struct {
+	// MaxValues caps the number of values kept per InfoItem; older values
+	// are dropped on write. Zero keeps every value.
+	MaxValues int
+	// OnWrite, when set, is called with the values of every accepted write
+	// once they are stored. It runs without the lock, so it may read the
+	// store.
+	OnWrite func(path df.Path, values []df.Value)
+
+	mu      sync.RWMutex
+	objects df.Objects
+}
+
+func NewStore(objects df.Objects) *Store {
+	return &Store{objects: *objects.DeepCopy()}
+}
+
+// Objects returns a copy of the stored tree.
+func (s *Store) Objects() df.Objects {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return *s.objects.DeepCopy()
+}
+
+// Write appends the values of every InfoItem in objects to the store.
+// Each InfoItem is checked with authorize first; the accepted ones are
+// applied together and the rejected ones are returned.
+func (s *Store) Write(objects df.Objects, authorize AuthorizeFunc) ([]Rejection, error) {
+	if err := validatePayload(objects); err != nil {
+		return nil, err
+	}
+
+	// authorize runs without the lock, so it may read the store; it sees
+	// the InfoItems as they were when the write started.
+	current := map[string]*df.InfoItem{}
+	s.mu.RLock()
+	objects.Walk(func(path df.Path, item *df.InfoItem) {
+		if stored := s.objects.InfoItem(path); stored != nil {
+			current[path.String()] = stored.DeepCopy()
+		}
+	})
+	s.mu.RUnlock()
+
+	type write struct {
+		path df.Path
+		item df.InfoItem
+	}
+	accepted := []write{}
+	rejected := []Rejection{}
+	objects.Walk(func(path df.Path, item *df.InfoItem) {
+		var err error
+		if len(item.Values) == 0 {
+			err = ErrNoValues
+		} else if authorize != nil {
+			err = authorize(path, current[path.String()], *item)
+		}
+		if err != nil {
+			rejected = append(rejected, Rejection{path, err})
+			return
+		}
+		accepted = append(accepted, write{path, *item})
+	})
+
+	s.mu.Lock()
+	for _, w := range accepted {
+		item := s.objects.Add(w.path, w.item.Values...)
+		if s.MaxValues > 0 && len(item.Values) > s.MaxValues {
+			item.Values = append([]df.Value(nil), item.Values[len(item.Values)-s.MaxValues:]...)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.OnWrite != nil {
+		for _, w := range accepted {
+			s.OnWrite(w.path, w.item.Values)
+		}
+	}
+	return rejected, nil
+}
+
+// HandleWrite applies an O-MI write request to the store. When every
+// InfoItem is accepted the response has a single result; otherwise it has
+// one result per InfoItem.
+func (s *Store) HandleWrite(write *mi.WriteRequest, authorize AuthorizeFunc) *mi.Response {
+	objects, err := decodePayload(write.MsgFormat, write.Message)
+	if err != nil {
+		return &mi.Response{Results: []mi.RequestResult{errorResult("400", err.Error())}}
+	}
+
+	rejected, err := s.Write(*objects, authorize)
+	if err != nil {
+		return &mi.Response{Results: []mi.RequestResult{errorResult("400", err.Error())}}
+	}
+	if len(rejected) == 0 {
+		return &mi.Response{Results: []mi.RequestResult{mi.RequestResult{Return: &mi.Return{ReturnCode: "200"}}}}
+	}
+
+	response := &mi.Response{}
+	next := 0
+	objects.Walk(func(path df.Path, item *df.InfoItem) {
+		result := mi.RequestResult{Return: &mi.Return{ReturnCode: "200"}}
+		if next < len(rejected) && pathEqual(rejected[next].Path, path) {
+			result = errorResult(returnCode(rejected[next].Err), rejected[next].Err.Error())
+			next++
+		}
+		target := df.Objects{}
+		target.Add(path)
+		if message, err := mi.EncodePayload("odf", target); err == nil {
+			result.MsgFormat = "odf"
+			result.Message = message
+		}
+		response.Results = append(response.Results, result)
+	})
+	return response
+}
+
+func decodePayload(format string, message *mi.Message) (*df.Objects, error) {
+	payload, err := mi.DecodePayload(format, message)
+	if err == mi.ErrNoMessage {
+		return nil, errors.New("node: request has no msg")
+	}
+	if err != nil {
+		return nil, err
+	}
+	objects, ok := payload.(*df.Objects)
+	if !ok {
+		return nil, fmt.Errorf("node: unsupported msgformat %q", format)
+	}
+	return objects, nil
+}
+
+func validatePayload(objects df.Objects) error {
+	var validate func(parent df.Path, objects []df.Object) error
+	validate = func(parent df.Path, objects []df.Object) error {
+		for i := range objects {
+			id := objects[i].ID()
+			if id == "" {
+				return fmt.Errorf("node: Object without id under %s", parent)
+			}
+			path := parent.Child(id)
+			for _, item := range objects[i].InfoItems {
+				if item.Name == "" {
+					return fmt.Errorf("node: InfoItem without name under %s", path)
+				}
+			}
+			if err := validate(path, objects[i].Objects); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return validate(df.Path{}, objects.Objects)
+}
+
+func returnCode(err error) string {
+	if errors.Is(err, ErrForbidden) {
+		return "403"
+	}
+	return "400"
+}
+
+func pathEqual(a, b df.Path) bool {
+	return len(a) == len(b) && a.HasPrefix(b)
+}
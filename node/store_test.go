@@ -0,0 +1,190 @@
+package node
+
+import (
+	"errors"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func loadWriteRequest(t *testing.T) *mi.WriteRequest {
+	data, err := ioutil.ReadFile("../mi/examples/write_request.xml")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	v, err := mi.Unmarshal(data)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return v.Write
+}
+
+func TestStoreWriteAppliesValues(t *testing.T) {
+	store := NewStore(df.Objects{})
+	response := store.HandleWrite(loadWriteRequest(t), nil)
+	if assert.Len(t, response.Results, 1) {
+		assert.Equal(t, "200", response.Results[0].Return.ReturnCode)
+	}
+
+	objects := store.Objects()
+	item := objects.InfoItem(df.ParsePath("SmartFridge22334411/FridgeTemperatureSetpoint"))
+	if assert.NotNil(t, item) && assert.Len(t, item.Values, 1) {
+		assert.Equal(t, "3.5", item.Values[0].Text)
+	}
+}
+
+func TestStoreWriteWithRejectedItems(t *testing.T) {
+	store := NewStore(df.Objects{})
+	authorize := func(path df.Path, current *df.InfoItem, item df.InfoItem) error {
+		if item.Name == "FreezerTemperatureSetpoint" {
+			return ErrForbidden
+		}
+		return nil
+	}
+
+	response := store.HandleWrite(loadWriteRequest(t), authorize)
+	if assert.Len(t, response.Results, 2) {
+		assert.Equal(t, "200", response.Results[0].Return.ReturnCode)
+		assert.Equal(t, "403", response.Results[1].Return.ReturnCode)
+		objects, err := df.Unmarshal([]byte(response.Results[1].Message.Data))
+		if assert.Nil(t, err) {
+			assert.NotNil(t, objects.InfoItem(df.ParsePath("SmartFridge22334411/FreezerTemperatureSetpoint")))
+		}
+	}
+
+	objects := store.Objects()
+	assert.NotNil(t, objects.InfoItem(df.ParsePath("SmartFridge22334411/FridgeTemperatureSetpoint")))
+	assert.Nil(t, objects.InfoItem(df.ParsePath("SmartFridge22334411/FreezerTemperatureSetpoint")))
+}
+
+func TestStoreWriteWithValidationError(t *testing.T) {
+	store := NewStore(df.Objects{})
+	authorize := func(path df.Path, current *df.InfoItem, item df.InfoItem) error {
+		return errors.New("value out of range")
+	}
+
+	rejected, err := store.Write(df.Objects{Objects: []df.Object{df.Object{
+		Id:        &df.QLMID{Text: "Fridge"},
+		InfoItems: []df.InfoItem{df.InfoItem{Name: "Setpoint", Values: []df.Value{df.Value{Text: "99"}}}},
+	}}}, authorize)
+	if assert.Nil(t, err) && assert.Len(t, rejected, 1) {
+		assert.Equal(t, df.ParsePath("Fridge/Setpoint"), rejected[0].Path)
+		assert.Equal(t, "400", returnCode(rejected[0].Err))
+	}
+}
+
+func TestStoreWriteWithInvalidPayload(t *testing.T) {
+	store := NewStore(df.Objects{})
+	response := store.HandleWrite(&mi.WriteRequest{
+		MsgFormat: "odf",
+		Message:   &mi.Message{Data: `<Objects><Object><InfoItem name="Setpoint"><value>1</value></InfoItem></Object></Objects>`},
+	}, nil)
+	if assert.Len(t, response.Results, 1) {
+		assert.Equal(t, "400", response.Results[0].Return.ReturnCode)
+	}
+	assert.Len(t, store.Objects().Objects, 0)
+}
+
+func TestStoreWriteWithItemWithoutValues(t *testing.T) {
+	store := NewStore(df.Objects{})
+	rejected, err := store.Write(df.Objects{Objects: []df.Object{df.Object{
+		Id:        &df.QLMID{Text: "Fridge"},
+		InfoItems: []df.InfoItem{df.InfoItem{Name: "Setpoint"}},
+	}}}, nil)
+	if assert.Nil(t, err) && assert.Len(t, rejected, 1) {
+		assert.Equal(t, ErrNoValues, rejected[0].Err)
+	}
+}
+
+func TestStoreWriteKeepsMaxValues(t *testing.T) {
+	store := NewStore(df.Objects{})
+	store.MaxValues = 2
+	written := []string{}
+	store.OnWrite = func(path df.Path, values []df.Value) {
+		for _, v := range values {
+			written = append(written, path.String()+"="+v.Text)
+		}
+	}
+
+	objects := df.Objects{}
+	objects.Add(df.ParsePath("Fridge/Power"), df.Value{Text: "1"}, df.Value{Text: "2"}, df.Value{Text: "3"})
+	_, err := store.Write(objects, nil)
+	if assert.Nil(t, err) {
+		stored := store.Objects()
+		item := stored.InfoItem(df.ParsePath("Fridge/Power"))
+		assert.Equal(t, []df.Value{df.Value{Text: "2"}, df.Value{Text: "3"}}, item.Values)
+		assert.Equal(t, []string{"Objects/Fridge/Power=1", "Objects/Fridge/Power=2", "Objects/Fridge/Power=3"}, written)
+	}
+}
+
+func TestStoreWriteEnforcesMetaData(t *testing.T) {
+	writable, readOnly := true, false
+	objects := df.Objects{}
+	objects.Add(df.ParsePath("SmartFridge22334411/FridgeTemperatureSetpoint")).MetaData =
+		df.Meta{Format: "xs:double", Writable: &writable}.MetaData()
+	objects.Add(df.ParsePath("SmartFridge22334411/FreezerTemperatureSetpoint")).MetaData =
+		df.Meta{Writable: &readOnly}.MetaData()
+	store := NewStore(objects)
+
+	calls := 0
+	authorize := EnforceMetaData(func(path df.Path, current *df.InfoItem, item df.InfoItem) error {
+		calls++
+		return nil
+	})
+	response := store.HandleWrite(loadWriteRequest(t), authorize)
+	if assert.Len(t, response.Results, 2) {
+		assert.Equal(t, "200", response.Results[0].Return.ReturnCode)
+		assert.Equal(t, "403", response.Results[1].Return.ReturnCode)
+	}
+	assert.Equal(t, 1, calls)
+
+	update := df.Objects{}
+	update.Add(df.ParsePath("SmartFridge22334411/FridgeTemperatureSetpoint"), df.Value{Text: "cold"})
+	update.Add(df.ParsePath("SmartFridge22334411/Door"), df.Value{Text: "open"})
+	rejected, err := store.Write(update, EnforceMetaData(nil))
+	if assert.Nil(t, err) && assert.Len(t, rejected, 1) {
+		assert.True(t, errors.Is(rejected[0].Err, df.ErrFormat))
+		assert.Equal(t, "400", returnCode(rejected[0].Err))
+	}
+	stored := store.Objects()
+	assert.NotNil(t, stored.InfoItem(df.ParsePath("SmartFridge22334411/Door")))
+}
+
+func TestStoreWriteAuthorizeCanReadStore(t *testing.T) {
+	store := NewStore(df.Objects{})
+	authorize := func(path df.Path, current *df.InfoItem, item df.InfoItem) error {
+		objects := store.Objects()
+		if objects.InfoItem(path) != nil {
+			return ErrForbidden
+		}
+		return nil
+	}
+	response := store.HandleWrite(loadWriteRequest(t), authorize)
+	if assert.Len(t, response.Results, 1) {
+		assert.Equal(t, "200", response.Results[0].Return.ReturnCode)
+	}
+	response = store.HandleWrite(loadWriteRequest(t), authorize)
+	if assert.Len(t, response.Results, 2) {
+		assert.Equal(t, "403", response.Results[0].Return.ReturnCode)
+		assert.Equal(t, "403", response.Results[1].Return.ReturnCode)
+	}
+}
+
+func TestStoreOnWriteCanReadStore(t *testing.T) {
+	store := NewStore(df.Objects{})
+	written := []string{}
+	store.OnWrite = func(path df.Path, values []df.Value) {
+		objects := store.Objects()
+		if item := objects.InfoItem(path); assert.NotNil(t, item) {
+			written = append(written, item.Values[len(item.Values)-1].Text)
+		}
+	}
+	objects := df.Objects{}
+	objects.Add(df.ParsePath("Fridge/Power"), df.Value{Text: "120"})
+	_, err := store.Write(objects, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"120"}, written)
+	}
+}
@@ -0,0 +1,176 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"sync"
+	"time"
+)
+
+var ErrUnknownSubscription = errors.New("node: unknown or expired subscription")
+
+// DefaultMaxBufferBytes is the MaxBytes of the subscriptions of nodes made
+// by New.
+const DefaultMaxBufferBytes = 1 << 20
+
+// Subscriptions buffers published values per subscription until a client
+// polls them with a read carrying the subscription's requestId.
+type Subscriptions struct {
+	// MaxBytes caps the approximate size of the values buffered for a single
+	// subscription. When it is exceeded the oldest values are dropped.
+	// Zero means no limit.
+	MaxBytes int
+
+	mu     sync.Mutex
+	subs   map[string]*subscription
+	nextId int
+	now    func() time.Time
+}
+
+type subscription struct {
+	paths   []df.Path
+	expires time.Time
+	values  []bufferedValue
+	size    int
+}
+
+type bufferedValue struct {
+	path  df.Path
+	value df.Value
+}
+
+func NewSubscriptions(maxBytes int) *Subscriptions {
+	return &Subscriptions{
+		MaxBytes: maxBytes,
+		subs:     map[string]*subscription{},
+		now:      time.Now,
+	}
+}
+
+// Subscribe starts buffering values published under any of paths and
+// returns the new subscription's requestId. A ttl of zero or less never
+// expires: the subscription keeps buffering, up to MaxBytes, until it is
+// cancelled.
+func (s *Subscriptions) Subscribe(paths []df.Path, ttl time.Duration) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextId++
+	id := fmt.Sprintf("REQ%d", s.nextId)
+	sub := &subscription{paths: paths}
+	if ttl > 0 {
+		sub.expires = s.now().Add(ttl)
+	}
+	s.subs[id] = sub
+	return id
+}
+
+// Publish adds values of the InfoItem at path to every subscription that covers it.
+func (s *Subscriptions) Publish(path df.Path, values ...df.Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	for _, sub := range s.subs {
+		if !sub.covers(path) {
+			continue
+		}
+		for _, value := range values {
+			sub.values = append(sub.values, bufferedValue{path, value})
+			sub.size += sizeOf(path, value)
+		}
+		if s.MaxBytes > 0 {
+			sub.trim(s.MaxBytes)
+		}
+	}
+}
+
+// Poll returns and clears the values buffered for the subscription id.
+func (s *Subscriptions) Poll(id string) (df.Objects, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	sub, ok := s.subs[id]
+	if !ok {
+		return df.Objects{}, ErrUnknownSubscription
+	}
+
+	objects := df.Objects{}
+	for _, v := range sub.values {
+		objects.Add(v.path, v.value)
+	}
+	sub.values = nil
+	sub.size = 0
+	return objects, nil
+}
+
+func (s *Subscriptions) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	if _, ok := s.subs[id]; !ok {
+		return ErrUnknownSubscription
+	}
+	delete(s.subs, id)
+	return nil
+}
+
+// Read answers a poll request with one result per requestId, reporting 404
+// for ids that are unknown or expired.
+func (s *Subscriptions) Read(read *mi.ReadRequest) *mi.Response {
+	response := &mi.Response{}
+	for _, id := range read.RequestIds {
+		requestId := id
+		objects, err := s.Poll(id.Text)
+		if err != nil {
+			response.Results = append(response.Results, mi.RequestResult{
+				Return:    &mi.Return{ReturnCode: "404", Description: "Not Found"},
+				RequestId: &requestId,
+			})
+			continue
+		}
+		result := odfResult(objects)
+		result.RequestId = &requestId
+		response.Results = append(response.Results, result)
+	}
+	return response
+}
+
+func (s *Subscriptions) expire() {
+	now := s.now()
+	for id, sub := range s.subs {
+		if !sub.expires.IsZero() && now.After(sub.expires) {
+			delete(s.subs, id)
+		}
+	}
+}
+
+func (sub *subscription) covers(path df.Path) bool {
+	for _, p := range sub.paths {
+		if path.HasPrefix(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sub *subscription) trim(maxBytes int) {
+	dropped := 0
+	for sub.size > maxBytes && dropped < len(sub.values) {
+		sub.size -= sizeOf(sub.values[dropped].path, sub.values[dropped].value)
+		dropped++
+	}
+	sub.values = append([]bufferedValue(nil), sub.values[dropped:]...)
+}
+
+func sizeOf(path df.Path, value df.Value) int {
+	size := len(value.Text) + len(value.Type) + len(value.DateTime) + 8
+	for _, p := range path {
+		size += len(p)
+	}
+	return size
+}
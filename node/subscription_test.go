@@ -0,0 +1,91 @@
+package node
+
+import (
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionPollReturnsAndClearsValues(t *testing.T) {
+	s := NewSubscriptions(0)
+	id := s.Subscribe([]df.Path{df.ParsePath("SmartFridge22334411")}, 0)
+
+	s.Publish(df.ParsePath("SmartFridge22334411/PowerConsumption"), df.Value{Text: "15.5"})
+	s.Publish(df.ParsePath("SmartFridge22334411/PowerConsumption"), df.Value{Text: "15.7"})
+	s.Publish(df.ParsePath("OtherFridge/PowerConsumption"), df.Value{Text: "1.0"})
+
+	objects, err := s.Poll(id)
+	if assert.Nil(t, err) {
+		item := objects.InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption"))
+		if assert.NotNil(t, item) && assert.Len(t, item.Values, 2) {
+			assert.Equal(t, "15.5", item.Values[0].Text)
+			assert.Equal(t, "15.7", item.Values[1].Text)
+		}
+		assert.Nil(t, objects.Object(df.ParsePath("OtherFridge")))
+	}
+
+	objects, err = s.Poll(id)
+	if assert.Nil(t, err) {
+		assert.Len(t, objects.Objects, 0)
+	}
+}
+
+func TestSubscriptionMemoryLimitDropsOldestValues(t *testing.T) {
+	path := df.ParsePath("Fridge/Power")
+	s := NewSubscriptions(2 * sizeOf(path, df.Value{Text: "1"}))
+	id := s.Subscribe([]df.Path{path}, 0)
+
+	s.Publish(path, df.Value{Text: "1"}, df.Value{Text: "2"}, df.Value{Text: "3"})
+
+	objects, err := s.Poll(id)
+	if assert.Nil(t, err) {
+		item := objects.InfoItem(path)
+		if assert.NotNil(t, item) && assert.Len(t, item.Values, 2) {
+			assert.Equal(t, "2", item.Values[0].Text)
+			assert.Equal(t, "3", item.Values[1].Text)
+		}
+	}
+}
+
+func TestSubscriptionExpires(t *testing.T) {
+	now := time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSubscriptions(0)
+	s.now = func() time.Time { return now }
+	id := s.Subscribe([]df.Path{df.ParsePath("Fridge")}, 10*time.Second)
+
+	_, err := s.Poll(id)
+	assert.Nil(t, err)
+
+	now = now.Add(11 * time.Second)
+	_, err = s.Poll(id)
+	assert.Equal(t, ErrUnknownSubscription, err)
+}
+
+func TestSubscriptionCancel(t *testing.T) {
+	s := NewSubscriptions(0)
+	id := s.Subscribe([]df.Path{df.ParsePath("Fridge")}, 0)
+	assert.Nil(t, s.Cancel(id))
+	assert.Equal(t, ErrUnknownSubscription, s.Cancel(id))
+}
+
+func TestSubscriptionReadByRequestId(t *testing.T) {
+	s := NewSubscriptions(0)
+	id := s.Subscribe([]df.Path{df.ParsePath("Fridge")}, 0)
+	s.Publish(df.ParsePath("Fridge/Power"), df.Value{Text: "43"})
+
+	response := s.Read(&mi.ReadRequest{RequestIds: []mi.Id{mi.Id{Text: id}, mi.Id{Text: "REQ404"}}})
+	if assert.Len(t, response.Results, 2) {
+		assert.Equal(t, "200", response.Results[0].Return.ReturnCode)
+		assert.Equal(t, id, response.Results[0].RequestId.Text)
+		assert.Equal(t, "odf", response.Results[0].MsgFormat)
+		objects, err := df.Unmarshal([]byte(response.Results[0].Message.Data))
+		if assert.Nil(t, err) {
+			assert.Equal(t, "43", objects.InfoItem(df.ParsePath("Fridge/Power")).Values[0].Text)
+		}
+
+		assert.Equal(t, "404", response.Results[1].Return.ReturnCode)
+		assert.Equal(t, "REQ404", response.Results[1].RequestId.Text)
+	}
+}
@@ -0,0 +1,33 @@
+package obix
+
+import (
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+)
+
+func init() {
+	mi.Register("obix", codec{})
+}
+
+// codec decodes obix msgs to []Obj. It encodes []Obj and Obj as they are
+// and translates df.Objects with FromODF.
+type codec struct{}
+
+func (codec) Decode(data []byte) (interface{}, error) {
+	return Unmarshal(data)
+}
+
+func (codec) Encode(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case []Obj:
+		return Marshal(v)
+	case Obj:
+		return Marshal([]Obj{v})
+	case df.Objects:
+		return Marshal(FromODF(v))
+	case *df.Objects:
+		return Marshal(FromODF(*v))
+	}
+	return nil, fmt.Errorf("cannot encode %T as obix", v)
+}
@@ -0,0 +1,150 @@
+// Package obix models the oBIX objects that appear as msgformat="obix"
+// payloads and translates them to and from O-DF.
+package obix
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+)
+
+const (
+	KindObj  = "obj"
+	KindReal = "real"
+	KindBool = "bool"
+	KindInt  = "int"
+	KindStr  = "str"
+)
+
+// Obj is an oBIX element. Kind is the element name, so the same type holds
+// obj containers and real, bool, int and str values.
+type Obj struct {
+	Kind        string
+	Name        string
+	Href        string
+	Is          string
+	DisplayName string
+	Val         string
+	Unit        string
+	Children    []Obj
+}
+
+func (o Obj) IsValue() bool {
+	switch o.Kind {
+	case KindReal, KindBool, KindInt, KindStr:
+		return true
+	}
+	return false
+}
+
+// Child returns the direct child with the given name or nil.
+func (o *Obj) Child(name string) *Obj {
+	for i := range o.Children {
+		if o.Children[i].Name == name {
+			return &o.Children[i]
+		}
+	}
+	return nil
+}
+
+func (o *Obj) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*o = Obj{Kind: start.Name.Local}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			o.Name = attr.Value
+		case "href":
+			o.Href = attr.Value
+		case "is":
+			o.Is = attr.Value
+		case "displayName":
+			o.DisplayName = attr.Value
+		case "val":
+			o.Val = attr.Value
+		case "unit":
+			o.Unit = attr.Value
+		}
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var child Obj
+			if err := d.DecodeElement(&child, &t); err != nil {
+				return err
+			}
+			o.Children = append(o.Children, child)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (o Obj) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	kind := o.Kind
+	if kind == "" {
+		kind = KindObj
+	}
+	start = xml.StartElement{Name: xml.Name{Local: kind}}
+	for _, attr := range []struct{ name, value string }{
+		{"name", o.Name},
+		{"href", o.Href},
+		{"is", o.Is},
+		{"displayName", o.DisplayName},
+		{"val", o.Val},
+		{"unit", o.Unit},
+	} {
+		if attr.value != "" {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attr.name}, Value: attr.value})
+		}
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, child := range o.Children {
+		if err := e.Encode(child); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Unmarshal decodes the oBIX elements of a msg body.
+func Unmarshal(data []byte) ([]Obj, error) {
+	var objs []Obj
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			var obj Obj
+			if err := d.DecodeElement(&obj, &start); err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+	}
+}
+
+func Marshal(objs []Obj) ([]byte, error) {
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	e.Indent("", "    ")
+	for _, obj := range objs {
+		if err := e.Encode(obj); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+package obix
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const thermostat = `<obj href="http://myhome/thermostat" >
+    <real name="spaceTemp" unit="obix:units/fahrenheit" val="67.2"/>
+    <real name="setpoint" unit="obix:units/fahrenheit" val="72.0"/>
+    <bool name="furnaceOn" val="true"/>
+</obj>`
+
+func TestUnmarshal(t *testing.T) {
+	objs, err := Unmarshal([]byte(thermostat))
+	if assert.Nil(t, err) && assert.Len(t, objs, 1) {
+		obj := objs[0]
+		assert.Equal(t, KindObj, obj.Kind)
+		assert.Equal(t, "http://myhome/thermostat", obj.Href)
+		if assert.Len(t, obj.Children, 3) {
+			assert.Equal(t, Obj{Kind: KindReal, Name: "spaceTemp", Unit: "obix:units/fahrenheit", Val: "67.2"}, obj.Children[0])
+			assert.True(t, obj.Children[2].IsValue())
+		}
+		if assert.NotNil(t, obj.Child("furnaceOn")) {
+			assert.Equal(t, "true", obj.Child("furnaceOn").Val)
+		}
+		assert.Nil(t, obj.Child("missing"))
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	_, err := Unmarshal([]byte(`<obj><real name="x"></obj>`))
+	assert.NotNil(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	objs := []Obj{
+		Obj{Href: "a", Children: []Obj{Obj{Kind: KindInt, Name: "count", Val: "3"}}},
+		Obj{Kind: KindStr, Name: "label", Val: "x & y"},
+	}
+	data, err := Marshal(objs)
+	if assert.Nil(t, err) {
+		assert.Equal(t, `<obj href="a">
+    <int name="count" val="3"></int>
+</obj>
+<str name="label" val="x &amp; y"></str>`, string(data))
+		decoded, err := Unmarshal(data)
+		assert.Nil(t, err)
+		objs[0].Kind = KindObj
+		assert.Equal(t, objs, decoded)
+	}
+}
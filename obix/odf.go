@@ -0,0 +1,167 @@
+package obix
+
+import (
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"strings"
+)
+
+// HrefIdType is the idType of the Object id that keeps the full href of
+// the oBIX object it was translated from.
+const HrefIdType = "obix:href"
+
+var valueTypes = map[string]string{
+	KindReal: "xs:double",
+	KindBool: "xs:boolean",
+	KindInt:  "xs:long",
+	KindStr:  "xs:string",
+}
+
+var valueKinds = map[string]string{
+	"xs:double":       KindReal,
+	"xs:float":        KindReal,
+	"xs:decimal":      KindReal,
+	"xs:boolean":      KindBool,
+	"xs:int":          KindInt,
+	"xs:integer":      KindInt,
+	"xs:long":         KindInt,
+	"xs:short":        KindInt,
+	"xs:byte":         KindInt,
+	"xs:unsignedInt":  KindInt,
+	"xs:unsignedLong": KindInt,
+}
+
+// ToODF translates oBIX objects to O-DF. Every obj becomes an Object whose
+// id is the last segment of its href, or its name for nested objs, and the
+// full href is kept as an extra id. Every real, bool, int and str child
+// becomes an InfoItem with a single value and a unit in its MetaData.
+func ToODF(objs []Obj) (df.Objects, error) {
+	objects := df.Objects{}
+	for _, obj := range objs {
+		if obj.Kind != KindObj {
+			return df.Objects{}, fmt.Errorf("obix: top level %s %q is not an obj", obj.Kind, obj.Name)
+		}
+		object, err := toObject(obj, true)
+		if err != nil {
+			return df.Objects{}, err
+		}
+		objects.Objects = append(objects.Objects, object)
+	}
+	return objects, nil
+}
+
+func toObject(obj Obj, top bool) (df.Object, error) {
+	id := hrefId(obj.Href)
+	if id == "" || !top && obj.Name != "" {
+		id = obj.Name
+	}
+	if id == "" {
+		return df.Object{}, fmt.Errorf("obix: obj without href or name")
+	}
+	object := df.Object{Type: obj.Is, Id: &df.QLMID{Text: id}}
+	if obj.Href != "" && obj.Href != id {
+		object.AddId(df.QLMID{IdType: HrefIdType, Text: obj.Href})
+	}
+	if obj.DisplayName != "" {
+		object.AddDescription(df.Description{Text: obj.DisplayName})
+	}
+	for _, child := range obj.Children {
+		switch {
+		case child.Kind == KindObj:
+			o, err := toObject(child, false)
+			if err != nil {
+				return df.Object{}, err
+			}
+			object.Objects = append(object.Objects, o)
+		case child.IsValue():
+			object.InfoItems = append(object.InfoItems, toInfoItem(child))
+		}
+	}
+	return object, nil
+}
+
+func toInfoItem(obj Obj) df.InfoItem {
+	name := obj.Name
+	if name == "" {
+		name = hrefId(obj.Href)
+	}
+	item := df.InfoItem{Name: name}
+	if obj.DisplayName != "" {
+		item.Description = &df.Description{Text: obj.DisplayName}
+	}
+	if obj.Unit != "" {
+		item.MetaData = &df.MetaData{InfoItems: []df.InfoItem{
+			df.InfoItem{Name: "unit", Values: []df.Value{df.Value{Type: "xs:string", Text: obj.Unit}}},
+		}}
+	}
+	if obj.Val != "" {
+		item.Values = []df.Value{df.Value{Type: valueTypes[obj.Kind], Text: obj.Val}}
+	}
+	return item
+}
+
+func hrefId(href string) string {
+	href = strings.TrimRight(href, "/")
+	return href[strings.LastIndex(href, "/")+1:]
+}
+
+// FromODF translates O-DF to oBIX. Objects become objs, using the href
+// kept by ToODF when there is one, and InfoItems become value elements of
+// the kind that matches the type of their latest value.
+func FromODF(objects df.Objects) []Obj {
+	objs := make([]Obj, len(objects.Objects))
+	for i := range objects.Objects {
+		objs[i] = fromObject(&objects.Objects[i], true)
+	}
+	return objs
+}
+
+func fromObject(object *df.Object, top bool) Obj {
+	obj := Obj{Kind: KindObj, Is: object.Type}
+	href := ""
+	for _, id := range object.Ids() {
+		if id.IdType == HrefIdType {
+			href = id.Text
+		}
+	}
+	switch {
+	case top && href == "":
+		obj.Href = object.ID()
+	case top:
+		obj.Href = href
+	default:
+		obj.Name, obj.Href = object.ID(), href
+	}
+	if object.Description != nil {
+		obj.DisplayName = object.Description.Text
+	}
+	for _, item := range object.InfoItems {
+		obj.Children = append(obj.Children, fromInfoItem(item))
+	}
+	for i := range object.Objects {
+		obj.Children = append(obj.Children, fromObject(&object.Objects[i], false))
+	}
+	return obj
+}
+
+func fromInfoItem(item df.InfoItem) Obj {
+	obj := Obj{Kind: KindStr, Name: item.Name}
+	if len(item.Values) > 0 {
+		value := item.Values[len(item.Values)-1]
+		obj.Val = strings.TrimSpace(value.Text)
+		if kind, ok := valueKinds[value.Type]; ok {
+			obj.Kind = kind
+		}
+	}
+	if item.Description != nil {
+		obj.DisplayName = item.Description.Text
+	}
+	if item.MetaData != nil {
+		for _, meta := range item.MetaData.InfoItems {
+			if meta.Name == "unit" && len(meta.Values) > 0 {
+				obj.Unit = strings.TrimSpace(meta.Values[len(meta.Values)-1].Text)
+			}
+		}
+	}
+	return obj
+}
@@ -0,0 +1,102 @@
+package obix
+
+import (
+	"github.com/qlm-iot/qlm/df"
+	"github.com/qlm-iot/qlm/mi"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestToODF(t *testing.T) {
+	objs, err := Unmarshal([]byte(thermostat))
+	if assert.Nil(t, err) {
+		objects, err := ToODF(objs)
+		if assert.Nil(t, err) && assert.Len(t, objects.Objects, 1) {
+			object := objects.Objects[0]
+			assert.Equal(t, "thermostat", object.ID())
+			assert.Equal(t, []df.QLMID{df.QLMID{IdType: HrefIdType, Text: "http://myhome/thermostat"}}, object.OtherIds)
+
+			item := objects.InfoItem(df.ParsePath("thermostat/spaceTemp"))
+			if assert.NotNil(t, item) {
+				assert.Equal(t, []df.Value{df.Value{Type: "xs:double", Text: "67.2"}}, item.Values)
+				if assert.NotNil(t, item.MetaData) && assert.Len(t, item.MetaData.InfoItems, 1) {
+					assert.Equal(t, "unit", item.MetaData.InfoItems[0].Name)
+					assert.Equal(t, "obix:units/fahrenheit", item.MetaData.InfoItems[0].Values[0].Text)
+				}
+			}
+			item = objects.InfoItem(df.ParsePath("thermostat/furnaceOn"))
+			if assert.NotNil(t, item) {
+				assert.Equal(t, []df.Value{df.Value{Type: "xs:boolean", Text: "true"}}, item.Values)
+				assert.Nil(t, item.MetaData)
+			}
+		}
+	}
+}
+
+func TestToODFErrors(t *testing.T) {
+	_, err := ToODF([]Obj{Obj{Kind: KindReal, Name: "x"}})
+	assert.NotNil(t, err)
+	_, err = ToODF([]Obj{Obj{Kind: KindObj, Href: "a", Children: []Obj{Obj{Kind: KindObj}}}})
+	assert.NotNil(t, err)
+}
+
+func TestRoundTrip(t *testing.T) {
+	objs := []Obj{Obj{
+		Kind:        KindObj,
+		Href:        "http://myhome/thermostat/",
+		Is:          "obix:Thermostat",
+		DisplayName: "Thermostat",
+		Children: []Obj{
+			Obj{Kind: KindReal, Name: "spaceTemp", Unit: "obix:units/fahrenheit", Val: "67.2"},
+			Obj{Kind: KindInt, Name: "mode", Val: "2"},
+			Obj{Kind: KindStr, Name: "label", Val: "hall"},
+			Obj{Kind: KindObj, Name: "schedule", Href: "schedule/", Children: []Obj{
+				Obj{Kind: KindBool, Name: "enabled", Val: "false"},
+			}},
+		},
+	}}
+	objects, err := ToODF(objs)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "thermostat", objects.Objects[0].ID())
+		assert.Equal(t, "schedule", objects.Objects[0].Objects[0].ID())
+		assert.Equal(t, objs, FromODF(objects))
+	}
+}
+
+func TestFromODF(t *testing.T) {
+	objects := df.Objects{}
+	objects.Add(df.ParsePath("Fridge/Power"), df.Value{Type: "xs:int", Text: "1"}, df.Value{Type: "xs:double", Text: " 43.5 "})
+	objects.Add(df.ParsePath("Fridge/Door/Open"), df.Value{Text: "no"})
+	assert.Equal(t, []Obj{Obj{Kind: KindObj, Href: "Fridge", Children: []Obj{
+		Obj{Kind: KindReal, Name: "Power", Val: "43.5"},
+		Obj{Kind: KindObj, Name: "Door", Children: []Obj{
+			Obj{Kind: KindStr, Name: "Open", Val: "no"},
+		}},
+	}}}, FromODF(objects))
+}
+
+func TestCodec(t *testing.T) {
+	data, err := ioutil.ReadFile("../mi/examples/multiple_payload_response.xml")
+	if assert.Nil(t, err) {
+		v, err := mi.Unmarshal(data)
+		if assert.Nil(t, err) {
+			payload, err := v.Response.Results[0].Payload()
+			if assert.Nil(t, err) && assert.IsType(t, []Obj{}, payload) {
+				objects, err := ToODF(payload.([]Obj))
+				assert.Nil(t, err)
+				assert.NotNil(t, objects.InfoItem(df.ParsePath("thermostat/setpoint")))
+
+				message, err := mi.EncodePayload("obix", objects)
+				if assert.Nil(t, err) {
+					decoded, err := mi.DecodePayload("obix", message)
+					assert.Nil(t, err)
+					assert.Equal(t, payload, decoded)
+				}
+			}
+		}
+	}
+
+	_, err = mi.EncodePayload("obix", 42)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,161 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"github.com/qlm-iot/qlm/df"
+	"strconv"
+	"strings"
+)
+
+var ErrNoUnit = errors.New("units: InfoItem has no unit")
+
+// Quantity is a numeric value together with its unit.
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+func (q Quantity) String() string {
+	return formatValue(q.Value) + " " + q.Unit.Symbol
+}
+
+// formatValue rounds to 12 significant digits to hide the noise of
+// converting through SI units.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 12, 64)
+}
+
+// In converts q to the unit to.
+func (q Quantity) In(to Unit) (Quantity, error) {
+	v, err := Convert(q.Value, q.Unit, to)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{v, to}, nil
+}
+
+// Of returns the unit declared by the MetaData of item.
+func Of(item *df.InfoItem) (Unit, error) {
+	meta, err := item.Meta()
+	if err != nil {
+		return Unit{}, err
+	}
+	if meta.Unit == "" {
+		return Unit{}, ErrNoUnit
+	}
+	return Parse(meta.Unit)
+}
+
+// Quantities returns the values of item in its declared unit.
+func Quantities(item *df.InfoItem) ([]Quantity, error) {
+	u, err := Of(item)
+	if err != nil {
+		return nil, err
+	}
+	quantities := make([]Quantity, len(item.Values))
+	for i, value := range item.Values {
+		v, err := strconv.ParseFloat(strings.TrimSpace(value.Text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("units: %s value %q is not a number", item.Name, value.Text)
+		}
+		quantities[i] = Quantity{v, u}
+	}
+	return quantities, nil
+}
+
+// ConvertInfoItem rewrites the values of item in the unit to and updates
+// the unit of its MetaData.
+func ConvertInfoItem(item *df.InfoItem, to Unit) error {
+	quantities, err := Quantities(item)
+	if err != nil {
+		return err
+	}
+	values := make([]df.Value, len(item.Values))
+	for i, q := range quantities {
+		if q, err = q.In(to); err != nil {
+			return err
+		}
+		values[i] = item.Values[i]
+		values[i].Text = formatValue(q.Value)
+	}
+	meta, _ := item.Meta()
+	meta.Unit = to.Symbol
+	item.Values = values
+	item.MetaData = meta.MetaData()
+	return nil
+}
+
+// ConvertAll returns a copy of objects where every InfoItem whose unit is
+// compatible with one of targets is converted to the first such target.
+// Use it on the result of a read or df.Select to get values in the units
+// the caller expects.
+func ConvertAll(objects df.Objects, targets ...Unit) (df.Objects, error) {
+	converted := *objects.DeepCopy()
+	var err error
+	converted.Walk(func(path df.Path, item *df.InfoItem) {
+		if err != nil {
+			return
+		}
+		u, uerr := Of(item)
+		if uerr != nil {
+			return
+		}
+		for _, target := range targets {
+			if u.Compatible(target) {
+				if cerr := ConvertInfoItem(item, target); cerr != nil {
+					err = fmt.Errorf("units: %s: %v", path, cerr)
+				}
+				return
+			}
+		}
+	})
+	if err != nil {
+		return df.Objects{}, err
+	}
+	return converted, nil
+}
+
+// Merge combines trees with df.Normalize. The values of an InfoItem that
+// appears in several trees are converted to the unit it has in the first
+// tree that declares one; incompatible units are an error.
+func Merge(trees ...df.Objects) (df.Objects, error) {
+	merged := df.Objects{}
+	targets := map[string]Unit{}
+	var err error
+	for _, tree := range trees {
+		tree := *tree.DeepCopy()
+		tree.Walk(func(path df.Path, item *df.InfoItem) {
+			if err != nil {
+				return
+			}
+			u, uerr := Of(item)
+			if uerr == ErrNoUnit {
+				return
+			}
+			if uerr != nil {
+				err = fmt.Errorf("units: %s: %v", path, uerr)
+				return
+			}
+			target, ok := targets[path.String()]
+			if !ok {
+				targets[path.String()] = u
+				return
+			}
+			if !u.Compatible(target) {
+				err = fmt.Errorf("%w: %s has %s and %s", ErrIncompatible, path, target, u)
+				return
+			}
+			if !u.Same(target) {
+				if cerr := ConvertInfoItem(item, target); cerr != nil {
+					err = fmt.Errorf("units: %s: %v", path, cerr)
+				}
+			}
+		})
+		if err != nil {
+			return df.Objects{}, err
+		}
+		merged.Objects = append(merged.Objects, tree.Objects...)
+	}
+	return df.Normalize(merged), nil
+}
@@ -0,0 +1,87 @@
+package units
+
+import (
+	"errors"
+	"github.com/qlm-iot/qlm/df"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func withUnit(objects *df.Objects, path, unit string, values ...string) {
+	item := objects.Add(df.ParsePath(path))
+	for _, v := range values {
+		item.Values = append(item.Values, df.Value{Type: "xs:double", Text: v})
+	}
+	item.MetaData = df.Meta{Unit: unit}.MetaData()
+}
+
+func TestQuantities(t *testing.T) {
+	data, err := ioutil.ReadFile("../df/examples/metadata_about_refrigerator_power_consumption.xml")
+	if assert.Nil(t, err) {
+		v, err := df.Unmarshal(data)
+		if assert.Nil(t, err) {
+			item := v.InfoItem(df.ParsePath("SmartFridge22334411/PowerConsumption"))
+			u, err := Of(item)
+			if assert.Nil(t, err) {
+				assert.True(t, u.Same(MustParse("W")))
+			}
+			item.Values = []df.Value{df.Value{Text: "43"}}
+			quantities, err := Quantities(item)
+			if assert.Nil(t, err) && assert.Len(t, quantities, 1) {
+				assert.Equal(t, "43 Watts", quantities[0].String())
+				kw, err := quantities[0].In(MustParse("kW"))
+				assert.Nil(t, err)
+				assert.Equal(t, "0.043 kW", kw.String())
+			}
+		}
+	}
+
+	_, err = Of(&df.InfoItem{Name: "x"})
+	assert.Equal(t, ErrNoUnit, err)
+}
+
+func TestConvertAll(t *testing.T) {
+	objects := df.Objects{}
+	withUnit(&objects, "Fridge/Power", "kW", "1.5")
+	withUnit(&objects, "Fridge/Temperature", "°F", "41")
+	withUnit(&objects, "Fridge/Door", "", "1")
+
+	converted, err := ConvertAll(objects, MustParse("W"), MustParse("°C"))
+	if assert.Nil(t, err) {
+		power := converted.InfoItem(df.ParsePath("Fridge/Power"))
+		assert.Equal(t, "1500", power.Values[0].Text)
+		assert.Equal(t, "xs:double", power.Values[0].Type)
+		meta, _ := power.Meta()
+		assert.Equal(t, "W", meta.Unit)
+		assert.Equal(t, "5", converted.InfoItem(df.ParsePath("Fridge/Temperature")).Values[0].Text)
+		assert.Equal(t, "1", converted.InfoItem(df.ParsePath("Fridge/Door")).Values[0].Text)
+	}
+	assert.Equal(t, "1.5", objects.InfoItem(df.ParsePath("Fridge/Power")).Values[0].Text)
+
+	withUnit(&objects, "Fridge/Label", "W", "high")
+	_, err = ConvertAll(objects, MustParse("kW"))
+	assert.NotNil(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	a, b, c := df.Objects{}, df.Objects{}, df.Objects{}
+	a.Add(df.ParsePath("Fridge/Power"), df.Value{UnixTime: 1, Text: "1500"}).MetaData = df.Meta{Unit: "W"}.MetaData()
+	b.Add(df.ParsePath("Fridge/Power"), df.Value{UnixTime: 2, Text: "1.2"}).MetaData = df.Meta{Unit: "kW"}.MetaData()
+	b.Add(df.ParsePath("Fridge/Energy"), df.Value{UnixTime: 2, Text: "3"}).MetaData = df.Meta{Unit: "kWh"}.MetaData()
+
+	merged, err := Merge(a, b)
+	if assert.Nil(t, err) {
+		power := merged.InfoItem(df.ParsePath("Fridge/Power"))
+		if assert.Len(t, power.Values, 2) {
+			assert.Equal(t, "1500", power.Values[0].Text)
+			assert.Equal(t, "1200", power.Values[1].Text)
+		}
+		energy := merged.InfoItem(df.ParsePath("Fridge/Energy"))
+		assert.Equal(t, "3", energy.Values[0].Text)
+	}
+
+	c.Add(df.ParsePath("Fridge/Power"), df.Value{Text: "5"}).MetaData = df.Meta{Unit: "°C"}.MetaData()
+	_, err = Merge(a, c)
+	assert.True(t, errors.Is(err, ErrIncompatible))
+}
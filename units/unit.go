@@ -0,0 +1,281 @@
+// Package units parses the free text units of O-DF MetaData and converts
+// values between compatible units.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownUnit  = errors.New("units: unknown unit")
+	ErrIncompatible = errors.New("units: incompatible units")
+)
+
+// Dimension holds the exponents of the SI base quantities length, mass,
+// time, electric current, temperature, amount of substance and luminous
+// intensity.
+type Dimension [7]int
+
+// Unit converts to SI base units as value*Scale + Offset. Only temperatures
+// such as °C have an Offset.
+type Unit struct {
+	Symbol string
+	Scale  float64
+	Offset float64
+	Dim    Dimension
+}
+
+func (u Unit) String() string {
+	return u.Symbol
+}
+
+// Compatible reports whether values can be converted between u and other.
+func (u Unit) Compatible(other Unit) bool {
+	return u.Dim == other.Dim
+}
+
+// Same reports whether u and other differ only by symbol.
+func (u Unit) Same(other Unit) bool {
+	return u.Dim == other.Dim && u.Scale == other.Scale && u.Offset == other.Offset
+}
+
+// Convert converts value from the unit from to the unit to.
+func Convert(value float64, from, to Unit) (float64, error) {
+	if !from.Compatible(to) {
+		return 0, fmt.Errorf("%w: %s and %s", ErrIncompatible, from, to)
+	}
+	if from.Same(to) {
+		return value, nil
+	}
+	return (value*from.Scale + from.Offset - to.Offset) / to.Scale, nil
+}
+
+func dim(exponents ...int) Dimension {
+	var d Dimension
+	copy(d[:], exponents)
+	return d
+}
+
+type symbol struct {
+	unit       Unit
+	prefixable bool
+}
+
+var (
+	length      = dim(1)
+	mass        = dim(0, 1)
+	duration    = dim(0, 0, 1)
+	current     = dim(0, 0, 0, 1)
+	temperature = dim(0, 0, 0, 0, 1)
+	amount      = dim(0, 0, 0, 0, 0, 1)
+	luminosity  = dim(0, 0, 0, 0, 0, 0, 1)
+	energy      = dim(2, 1, -2)
+	power       = dim(2, 1, -3)
+	pressure    = dim(-1, 1, -2)
+)
+
+var symbols = map[string]symbol{}
+
+func init() {
+	for _, s := range []struct {
+		symbol     string
+		scale      float64
+		dim        Dimension
+		prefixable bool
+	}{
+		{"1", 1, Dimension{}, false},
+		{"%", 0.01, Dimension{}, false},
+		{"ppm", 1e-6, Dimension{}, false},
+		{"m", 1, length, true},
+		{"g", 1e-3, mass, true},
+		{"t", 1e3, mass, false},
+		{"s", 1, duration, true},
+		{"min", 60, duration, false},
+		{"h", 3600, duration, false},
+		{"d", 86400, duration, false},
+		{"A", 1, current, true},
+		{"K", 1, temperature, true},
+		{"mol", 1, amount, true},
+		{"cd", 1, luminosity, true},
+		{"Hz", 1, dim(0, 0, -1), true},
+		{"N", 1, dim(1, 1, -2), true},
+		{"Pa", 1, pressure, true},
+		{"bar", 1e5, pressure, true},
+		{"J", 1, energy, true},
+		{"Wh", 3600, energy, true},
+		{"W", 1, power, true},
+		{"VA", 1, power, true},
+		{"C", 1, dim(0, 0, 1, 1), true},
+		{"V", 1, dim(2, 1, -3, -1), true},
+		{"Ohm", 1, dim(2, 1, -3, -2), true},
+		{"Ω", 1, dim(2, 1, -3, -2), true},
+		{"lm", 1, luminosity, true},
+		{"lx", 1, dim(-2, 0, 0, 0, 0, 0, 1), true},
+		{"l", 1e-3, dim(3), true},
+		{"L", 1e-3, dim(3), true},
+		{"[in_i]", 0.0254, length, false},
+		{"[ft_i]", 0.3048, length, false},
+		{"[lb_av]", 0.45359237, mass, false},
+		{"[psi]", 6894.757293168, pressure, false},
+	} {
+		symbols[s.symbol] = symbol{Unit{Symbol: s.symbol, Scale: s.scale, Dim: s.dim}, s.prefixable}
+	}
+	celsius := Unit{Symbol: "°C", Scale: 1, Offset: 273.15, Dim: temperature}
+	fahrenheit := Unit{Symbol: "°F", Scale: 5.0 / 9, Offset: 273.15 - 32*5.0/9, Dim: temperature}
+	for _, s := range []string{"°C", "Cel"} {
+		symbols[s] = symbol{celsius, false}
+	}
+	for _, s := range []string{"°F", "[degF]"} {
+		symbols[s] = symbol{fahrenheit, false}
+	}
+
+	for alias, s := range map[string]string{
+		"watt": "W", "watts": "W", "kilowatt": "kW", "kilowatts": "kW",
+		"watt hour": "Wh", "watt hours": "Wh", "kilowatt hour": "kWh", "kilowatt hours": "kWh",
+		"joule": "J", "joules": "J",
+		"celsius": "°C", "degree celsius": "°C", "degrees celsius": "°C", "degc": "°C", "°c": "°C",
+		"fahrenheit": "°F", "degree fahrenheit": "°F", "degrees fahrenheit": "°F", "degf": "°F", "°f": "°F",
+		"kelvin": "K", "meter": "m", "meters": "m", "metre": "m", "metres": "m",
+		"second": "s", "seconds": "s", "minute": "min", "minutes": "min", "hour": "h", "hours": "h",
+		"volt": "V", "volts": "V", "ampere": "A", "amperes": "A", "amp": "A", "amps": "A",
+		"hertz": "Hz", "pascal": "Pa", "pascals": "Pa", "lux": "lx",
+		"liter": "l", "liters": "l", "litre": "l", "litres": "l",
+		"percent": "%", "inch": "[in_i]", "inches": "[in_i]", "foot": "[ft_i]", "feet": "[ft_i]",
+		"pound": "[lb_av]", "pounds": "[lb_av]", "psi": "[psi]",
+	} {
+		u, err := parse(s)
+		if err != nil {
+			panic(err)
+		}
+		aliases[alias] = u
+	}
+}
+
+var prefixes = map[string]float64{
+	"Y": 1e24, "Z": 1e21, "E": 1e18, "P": 1e15, "T": 1e12, "G": 1e9, "M": 1e6, "k": 1e3,
+	"h": 1e2, "da": 1e1, "d": 1e-1, "c": 1e-2, "m": 1e-3, "u": 1e-6, "µ": 1e-6,
+	"n": 1e-9, "p": 1e-12, "f": 1e-15, "a": 1e-18, "z": 1e-21, "y": 1e-24,
+}
+
+var (
+	aliasesMu sync.RWMutex
+	aliases   = map[string]Unit{}
+)
+
+// Register adds a vendor specific name for u. Names are matched case
+// insensitively and take precedence over symbols.
+func Register(name string, u Unit) {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	aliases[strings.ToLower(strings.TrimSpace(name))] = u
+}
+
+// Parse parses a unit: a registered name such as "Watts" or
+// "obix:units/fahrenheit", or an expression of SI and UCUM symbols with
+// optional prefixes and exponents, such as "kW", "m/s", "km.h-1" or "m^2".
+// The returned Unit keeps s as its Symbol.
+func Parse(s string) (Unit, error) {
+	s = strings.TrimSpace(s)
+	u, err := parse(s)
+	if err != nil {
+		return Unit{}, err
+	}
+	u.Symbol = s
+	return u, nil
+}
+
+func MustParse(s string) Unit {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func parse(s string) (Unit, error) {
+	if u, ok := lookupAlias(s); ok {
+		return u, nil
+	}
+	if u, ok := symbols[s]; ok {
+		return u.unit, nil
+	}
+	if s == "" {
+		return Unit{}, fmt.Errorf("%w: empty unit", ErrUnknownUnit)
+	}
+
+	u := Unit{Symbol: s, Scale: 1}
+	for i, part := range strings.Split(s, "/") {
+		for _, term := range strings.FieldsFunc(part, func(r rune) bool {
+			return r == '.' || r == '*' || r == '·' || r == ' '
+		}) {
+			t, exponent, err := parseTerm(term)
+			if err != nil {
+				return Unit{}, fmt.Errorf("%w: %q in %q", ErrUnknownUnit, term, s)
+			}
+			if t.Offset != 0 {
+				return Unit{}, fmt.Errorf("%w: %s cannot be combined in %q", ErrIncompatible, term, s)
+			}
+			if i > 0 {
+				exponent = -exponent
+			}
+			u.Scale *= math.Pow(t.Scale, float64(exponent))
+			for j := range u.Dim {
+				u.Dim[j] += t.Dim[j] * exponent
+			}
+		}
+	}
+	return u, nil
+}
+
+func lookupAlias(s string) (Unit, bool) {
+	name := strings.ToLower(s)
+	if strings.HasPrefix(name, "obix:units/") {
+		name = strings.Replace(strings.TrimPrefix(name, "obix:units/"), "_", " ", -1)
+	}
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	u, ok := aliases[name]
+	return u, ok
+}
+
+var superscripts = strings.NewReplacer("⁻", "-", "¹", "1", "²", "2", "³", "3", "⁴", "4")
+
+func parseTerm(term string) (Unit, int, error) {
+	term = superscripts.Replace(term)
+	end := len(term)
+	for end > 0 && term[end-1] >= '0' && term[end-1] <= '9' {
+		end--
+	}
+	if end > 0 && end < len(term) && term[end-1] == '-' {
+		end--
+	}
+	exponent := 1
+	if end < len(term) && end > 0 {
+		e, err := strconv.Atoi(term[end:])
+		if err != nil {
+			return Unit{}, 0, err
+		}
+		exponent = e
+		term = strings.TrimSuffix(term[:end], "^")
+	}
+
+	if s, ok := symbols[term]; ok {
+		return s.unit, exponent, nil
+	}
+	for prefix, scale := range prefixes {
+		if s, ok := symbols[strings.TrimPrefix(term, prefix)]; ok && s.prefixable && strings.HasPrefix(term, prefix) {
+			u := s.unit
+			u.Scale *= scale
+			return u, exponent, nil
+		}
+	}
+	if u, ok := lookupAlias(term); ok {
+		return u, exponent, nil
+	}
+	return Unit{}, 0, ErrUnknownUnit
+}
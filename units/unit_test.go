@@ -0,0 +1,78 @@
+package units
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]Unit{
+		"W":                     Unit{Scale: 1, Dim: power},
+		"Watts":                 Unit{Scale: 1, Dim: power},
+		"kW":                    Unit{Scale: 1e3, Dim: power},
+		"kWh":                   Unit{Scale: 3.6e6, Dim: energy},
+		"kilowatt hours":        Unit{Scale: 3.6e6, Dim: energy},
+		"hPa":                   Unit{Scale: 100, Dim: pressure},
+		"mbar":                  Unit{Scale: 100, Dim: pressure},
+		"min":                   Unit{Scale: 60, Dim: duration},
+		"m/s":                   Unit{Scale: 1, Dim: dim(1, 0, -1)},
+		"m.s-1":                 Unit{Scale: 1, Dim: dim(1, 0, -1)},
+		"m^3":                   Unit{Scale: 1, Dim: dim(3)},
+		"m³":                    Unit{Scale: 1, Dim: dim(3)},
+		"%":                     Unit{Scale: 0.01},
+		"°C":                    Unit{Scale: 1, Offset: 273.15, Dim: temperature},
+		"Cel":                   Unit{Scale: 1, Offset: 273.15, Dim: temperature},
+		"obix:units/fahrenheit": Unit{Scale: 5.0 / 9, Offset: 273.15 - 32*5.0/9, Dim: temperature},
+		" cd ":                  Unit{Scale: 1, Dim: luminosity},
+	} {
+		u, err := Parse(s)
+		if assert.Nil(t, err, s) {
+			assert.InDelta(t, expected.Scale, u.Scale, 1e-9*expected.Scale, s)
+			assert.Equal(t, expected.Offset, u.Offset, s)
+			assert.Equal(t, expected.Dim, u.Dim, s)
+		}
+	}
+	assert.Equal(t, "Watts", MustParse(" Watts ").Symbol)
+
+	for _, s := range []string{"", "furlongs", "kmin", "°C/s", "m^x"} {
+		_, err := Parse(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	for _, c := range []struct {
+		value    float64
+		from, to string
+		expected float64
+	}{
+		{1.5, "kW", "W", 1500},
+		{2, "kWh", "J", 7.2e6},
+		{100, "°C", "K", 373.15},
+		{67.2, "obix:units/fahrenheit", "Cel", 19.5556},
+		{36, "km/h", "m/s", 10},
+		{50, "%", "1", 0.5},
+		{1, "[psi]", "kPa", 6.8948},
+	} {
+		v, err := Convert(c.value, MustParse(c.from), MustParse(c.to))
+		if assert.Nil(t, err) {
+			assert.InDelta(t, c.expected, v, 1e-4, "%s -> %s", c.from, c.to)
+		}
+	}
+
+	_, err := Convert(1, MustParse("W"), MustParse("Wh"))
+	assert.True(t, errors.Is(err, ErrIncompatible))
+}
+
+func TestRegister(t *testing.T) {
+	_, err := Parse("VendorKiloWatt")
+	assert.True(t, errors.Is(err, ErrUnknownUnit))
+
+	Register("vendorkilowatt", MustParse("kW"))
+	u, err := Parse("VendorKiloWatt")
+	if assert.Nil(t, err) {
+		assert.True(t, u.Same(MustParse("kW")))
+		assert.Equal(t, "VendorKiloWatt", u.String())
+	}
+}